@@ -2,14 +2,13 @@ package parsers
 
 import (
 	"encoding/json"
-	"github.com/ashbeelghouri/jsonschematics"
+	"github.com/ashbeelghouri/jsonschematics/utils"
 	"io"
 	"net/http"
-	"strings"
 )
 
 func ParseRequest(r *http.Request) (map[string]interface{}, error) {
-	var headers map[string]string
+	headers := map[string]interface{}{}
 	for key, values := range r.Header {
 		headers[key] = values[0]
 	}
@@ -19,28 +18,24 @@ func ParseRequest(r *http.Request) (map[string]interface{}, error) {
 		if err != nil {
 			return nil, err
 		}
-		err = json.Unmarshal(bodyBytes, &body)
-		if err != nil {
-			return nil, err
+		if len(bodyBytes) > 0 {
+			err = json.Unmarshal(bodyBytes, &body)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
-	body = jsonschematics.DeflateMap(body, ".")
-	splitPath := strings.Split(r.RequestURI, "?")
+	body = utils.DeflateMap(body, ".")
 	// get query parameters
 	query := map[string]interface{}{}
-	if splitPath[1] != "" {
-		for _, param := range strings.Split(splitPath[1], "&") {
-			kv := strings.Split(param, "=")
-			if len(kv) == 2 {
-				query[kv[0]] = kv[1]
-			}
-		}
+	for key, values := range r.URL.Query() {
+		query[key] = values[0]
 	}
 	// already in the FLAT mode
 	return map[string]interface{}{
 		"headers": headers,
 		"body":    body,
-		"path":    splitPath[0],
+		"path":    r.URL.Path,
 		"method":  r.Method,
 		"query":   query,
 	}, nil
@@ -0,0 +1,129 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenApiVersion is the version of the exported OpenAPI documents, 3.1 uses JSON Schema 2020-12 for its schemas
+const OpenApiVersion = "3.1.0"
+
+var colonParameter = regexp.MustCompile(`:([^/]+)`)
+var braceParameter = regexp.MustCompile(`\{([^/}]+)}`)
+
+// OpenApi converts the schema to an OpenAPI document with the title. Every endpoint becomes an operation of its path
+// and method, the global headers, Headers and Query become parameters and Body becomes the application/json request body.
+// The schemas of the fields are exported like JsonSchema of data/v0, so the validators are mapped to keywords
+func (s *Schema) OpenApi(title string) map[string]interface{} {
+	version := s.Version
+	if version == "" {
+		version = "1.0"
+	}
+	paths := map[string]interface{}{}
+	for _, key := range sortedEndpoints(s.Endpoints) {
+		endpoint := s.Endpoints[key]
+		path := endpoint.Path
+		if path == "" {
+			path = string(key)
+		}
+		path = colonParameter.ReplaceAllString(path, "{$1}")
+		method := strings.ToLower(endpoint.Type)
+		if method == "" {
+			method = "get"
+			if len(endpoint.Body) > 0 {
+				method = "post"
+			}
+		}
+
+		operation := map[string]interface{}{}
+		if endpoint.Path != "" && string(key) != fmt.Sprintf("%s %s", strings.ToUpper(method), endpoint.Path) {
+			operation["operationId"] = string(key)
+		}
+		var parameters []interface{}
+		for _, match := range braceParameter.FindAllStringSubmatch(path, -1) {
+			parameters = append(parameters, map[string]interface{}{
+				"name": match[1], "in": "path", "required": true, "schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		parameters = append(parameters, s.openApiParameters("header", "Global Headers", s.Global.Headers)...)
+		parameters = append(parameters, s.openApiParameters("header", "Headers", endpoint.Headers)...)
+		parameters = append(parameters, s.openApiParameters("query", "Query", endpoint.Query)...)
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+		if len(endpoint.Body) > 0 {
+			body := s.openApiSchema("Body", endpoint.Body)
+			requestBody := map[string]interface{}{
+				"content": map[string]interface{}{"application/json": map[string]interface{}{"schema": body}},
+			}
+			if _, required := body["required"]; required {
+				requestBody["required"] = true
+			}
+			operation["requestBody"] = requestBody
+		}
+
+		item, _ := paths[path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		item[method] = operation
+	}
+	return map[string]interface{}{
+		"openapi": OpenApiVersion,
+		"info":    map[string]interface{}{"title": title, "version": version},
+		"paths":   paths,
+	}
+}
+
+// ExportOpenApi returns the OpenApi document of the schema as indented JSON
+func (s *Schema) ExportOpenApi(title string) ([]byte, error) {
+	return json.MarshalIndent(s.OpenApi(title), "", "  ")
+}
+
+// openApiSchema is the JSON Schema of the fields
+func (s *Schema) openApiSchema(fieldType string, fields map[TargetKey]Field) map[string]interface{} {
+	schematics, _ := s.GetSchematics(fieldType, &fields)
+	schema := schematics.JsonSchema()
+	delete(schema, "$schema")
+	delete(schema, "x-version")
+	return schema
+}
+
+// openApiParameters are the parameters of the fields in the location, nested targets become object parameters
+func (s *Schema) openApiParameters(in string, fieldType string, fields map[TargetKey]Field) []interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+	schema := s.openApiSchema(fieldType, fields)
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := map[string]bool{}
+	if names, ok := schema["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	var parameters []interface{}
+	for _, name := range sortedKeys(properties) {
+		parameter := map[string]interface{}{"name": name, "in": in, "schema": properties[name]}
+		if required[name] {
+			parameter["required"] = true
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+func sortedEndpoints(endpoints map[EndpointKey]Endpoint) []EndpointKey {
+	keys := make([]EndpointKey, 0, len(endpoints))
+	for key := range endpoints {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
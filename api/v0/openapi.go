@@ -0,0 +1,254 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	jsonschematics "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// openApiMethods are the operations of a path item that become endpoints
+var openApiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LoadOpenApiFile reads the schema from an OpenAPI 3 document in JSON, see LoadOpenApi
+func LoadOpenApiFile(path string) (*Schema, []jsonschematics.Diagnostic, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return loadOpenApi(content, filepath.Dir(path))
+}
+
+// LoadOpenApi reads the schema from an OpenAPI 3 document in JSON. Every path and method becomes an endpoint named by
+// its operationId, or by the method and the path when it has none. The application/json request body becomes the Body
+// fields and the header and query parameters become the Headers and Query fields, their schemas are translated like
+// JsonSchemaFields of data/v0. What is not translated is returned as warnings with its path in the document
+func LoadOpenApi(content []byte) (*Schema, []jsonschematics.Diagnostic, error) {
+	return loadOpenApi(content, "")
+}
+
+func loadOpenApi(content []byte, dir string) (*Schema, []jsonschematics.Diagnostic, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, nil, err
+	}
+	version, _ := document["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, nil, fmt.Errorf("only OpenAPI 3 documents are supported, the openapi version is %q", version)
+	}
+	// only the references of the operations are resolved, a recursive schema is kept until it points back to itself
+	// and is reported where it recurses
+	var paths map[string]interface{}
+	if _, exists := document["paths"]; exists {
+		resolved, err := utils.ResolveReferencesKeepingCycles(content, dir, "/paths")
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(resolved, &paths); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	importer := openApiImporter{}
+	schema := Schema{
+		Locale:    "en",
+		Global:    Global{Headers: map[TargetKey]Field{}},
+		Endpoints: map[EndpointKey]Endpoint{},
+	}
+	if info, ok := document["info"].(map[string]interface{}); ok {
+		schema.Version, _ = info["version"].(string)
+	}
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		itemPointer := "#/paths/" + pointerEscape(path)
+		for _, method := range openApiMethods {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name := fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+			if operationId, ok := operation["operationId"].(string); ok && operationId != "" {
+				name = operationId
+			}
+			schema.Endpoints[EndpointKey(name)] = importer.endpoint(path, method, item, operation, itemPointer)
+		}
+	}
+	return &schema, importer.report, nil
+}
+
+type openApiImporter struct {
+	report []jsonschematics.Diagnostic
+}
+
+func (i *openApiImporter) warn(path string, format string, args ...interface{}) {
+	i.report = append(i.report, jsonschematics.Diagnostic{Path: path, Severity: jsonschematics.SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+func (i *openApiImporter) endpoint(path string, method string, item map[string]interface{}, operation map[string]interface{}, itemPointer string) Endpoint {
+	endpoint := Endpoint{
+		Path:    path,
+		Type:    strings.ToUpper(method),
+		Body:    map[TargetKey]Field{},
+		Headers: map[TargetKey]Field{},
+		Query:   map[TargetKey]Field{},
+	}
+	operationPointer := itemPointer + "/" + method
+
+	// the parameters of the operation override the parameters of the path with the same name and location
+	type parameter struct {
+		value   map[string]interface{}
+		pointer string
+	}
+	parameters := map[string]parameter{}
+	var order []string
+	for _, source := range []struct {
+		node    map[string]interface{}
+		pointer string
+	}{{item, itemPointer}, {operation, operationPointer}} {
+		list, _ := source.node["parameters"].([]interface{})
+		for index, p := range list {
+			value, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := value["name"].(string)
+			in, _ := value["in"].(string)
+			key := in + ":" + name
+			if _, exists := parameters[key]; !exists {
+				order = append(order, key)
+			}
+			parameters[key] = parameter{value: value, pointer: fmt.Sprintf("%s/parameters/%d", source.pointer, index)}
+		}
+	}
+	for _, key := range order {
+		p := parameters[key]
+		name, _ := p.value["name"].(string)
+		switch in, _ := p.value["in"].(string); in {
+		case "header":
+			i.parameter(endpoint.Headers, http.CanonicalHeaderKey(name), p.value, p.pointer)
+		case "query":
+			i.parameter(endpoint.Query, name, p.value, p.pointer)
+		case "path":
+			// the path parameters are matched by the path of the endpoint
+		default:
+			i.warn(p.pointer, "%s parameters are not translated", in)
+		}
+	}
+
+	if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+		content, _ := body["content"].(map[string]interface{})
+		for _, mediaType := range sortedKeys(content) {
+			pointer := operationPointer + "/requestBody/content/" + pointerEscape(mediaType)
+			if mediaType != "application/json" {
+				i.warn(pointer, "only application/json request bodies are translated")
+				continue
+			}
+			media, _ := content[mediaType].(map[string]interface{})
+			if bodySchema, ok := media["schema"].(map[string]interface{}); ok {
+				i.fields(endpoint.Body, bodySchema, pointer+"/schema")
+			}
+		}
+	}
+	return endpoint
+}
+
+// parameter adds the field of the header or query parameter
+func (i *openApiImporter) parameter(fields map[TargetKey]Field, name string, parameter map[string]interface{}, pointer string) {
+	property, ok := parameter["schema"].(map[string]interface{})
+	if !ok {
+		property = map[string]interface{}{}
+	}
+	if description, ok := parameter["description"].(string); ok {
+		if _, exists := property["description"]; !exists {
+			property = copyMap(property)
+			property["description"] = description
+		}
+	}
+	wrapper := map[string]interface{}{"properties": map[string]interface{}{name: property}}
+	if required, _ := parameter["required"].(bool); required {
+		wrapper["required"] = []interface{}{name}
+	}
+	translated, report := jsonschematics.JsonSchemaFields(wrapper, ".")
+	for _, diagnostic := range report {
+		diagnostic.Path = pointer + "/schema" + strings.TrimPrefix(diagnostic.Path, "#/properties/"+name)
+		i.report = append(i.report, diagnostic)
+	}
+	i.add(fields, translated, "", pointer+"/schema")
+}
+
+// fields adds the fields of the json schema
+func (i *openApiImporter) fields(fields map[TargetKey]Field, schema map[string]interface{}, pointer string) {
+	translated, report := jsonschematics.JsonSchemaFields(schema, ".")
+	for _, diagnostic := range report {
+		diagnostic.Path = pointer + strings.TrimPrefix(diagnostic.Path, "#")
+		i.report = append(i.report, diagnostic)
+	}
+	i.add(fields, translated, "", pointer)
+}
+
+func (i *openApiImporter) add(fields map[TargetKey]Field, translated map[jsonschematics.TargetKey]jsonschematics.Field, parent string, pointer string) {
+	for _, target := range jsonschematics.OrderedTargets(translated, nil) {
+		name := string(target)
+		if parent != "" {
+			name = parent + "." + name
+		}
+		fields[TargetKey(target)] = i.field(name, translated[target], pointer)
+	}
+}
+
+// field converts the translated field with its nested fields and items, name is the path of the field in the schema
+func (i *openApiImporter) field(name string, field jsonschematics.Field, pointer string) Field {
+	validators := map[TargetKey]Constant{}
+	for validator, constant := range field.Validators {
+		validators[TargetKey(validator)] = Constant{Attributes: constant.Attributes}
+	}
+	if field.Nullable || field.Default != nil {
+		i.warn(pointer, "nullable and default of %s are not translated", name)
+	}
+	converted := Field{
+		DependsOn:  field.DependsOn,
+		Name:       field.DisplayName,
+		Type:       field.Type,
+		Required:   field.IsRequired,
+		Validators: validators,
+	}
+	if len(field.Fields) > 0 {
+		converted.Fields = map[TargetKey]Field{}
+		i.add(converted.Fields, field.Fields, name, pointer)
+	}
+	if field.Items != nil {
+		items := i.field(name+".*", *field.Items, pointer)
+		converted.Items = &items
+	}
+	return converted
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m)+1)
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
+
+// pointerEscape escapes the key for a json pointer
+func pointerEscape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,104 @@
+package v0
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadOpenApi(t *testing.T) {
+	const requestId = "0b7e2f5c-4d8a-4c1e-9f3a-2a6b8c9d0e1f"
+	withId := map[string]string{"X-Request-Id": requestId}
+	tests := []struct {
+		file   string
+		report []string
+		cases  []requestCase
+	}{
+		{
+			file: "../../test-data/schema/api/openapi/example.json",
+			report: []string{
+				"warning: #/paths/~1users/post/requestBody/content/application~1json/schema: nullable and default of age are not translated",
+				"warning: #/paths/~1users/post/requestBody/content/application~1xml: only application/json request bodies are translated",
+				"warning: #/paths/~1users~1{id}/delete/parameters/1: cookie parameters are not translated",
+			},
+			cases: []requestCase{
+				{method: "GET", target: "/users?limit=5", header: withId},
+				{method: "GET", target: "/users?limit=500", header: withId, errors: []string{"limit"}},
+				{method: "GET", target: "/users", errors: []string{"X-Request-Id"}},
+				{method: "POST", target: "/users", header: withId, body: `{"name": "J", "email": "not-an-email"}`, errors: []string{"name", "email"}},
+				{method: "POST", target: "/users", header: withId, body: `{"name": "John", "email": "john@example.com", "age": 30}`},
+			},
+		},
+		{
+			file: "../../test-data/schema/api/openapi/nested.json",
+			report: []string{
+				"warning: #/paths/~1products/post/requestBody/content/application~1json/schema/properties/category/properties/children/items/$ref: the recursive reference #/components/schemas/Category is not translated",
+			},
+			// the nested objects are optional, their required fields are only required when they are provided
+			cases: []requestCase{
+				{method: "POST", target: "/products", body: `{"name": "chair"}`},
+				{method: "POST", target: "/products", body: `{"name": "chair", "tags": []}`},
+				{method: "POST", target: "/products", body: `{"name": "chair", "dimensions": {"width": 40}, "category": {"title": "home"}}`},
+				{method: "POST", target: "/products", body: `{"name": "chair", "dimensions": {"height": 90}}`, errors: []string{"dimensions.width"}},
+				{method: "POST", target: "/products", body: `{"name": "chair", "tags": [{"label": "wood"}, {}]}`, errors: []string{"tags.1.label"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		schema, report, err := LoadOpenApiFile(test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, diagnostic := range report {
+			got = append(got, diagnostic.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(test.report, "\n") {
+			t.Fatalf("%s: expected the report\n%s\ngot\n%s", test.file, strings.Join(test.report, "\n"), strings.Join(got, "\n"))
+		}
+		runRequestCases(t, schema, test.cases)
+	}
+}
+
+func TestLoadOpenApiEndpoints(t *testing.T) {
+	schema, _, err := LoadOpenApiFile("../../test-data/schema/api/openapi/example.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Version != "1.2.0" || len(schema.Endpoints) != 3 {
+		t.Fatalf("unexpected schema %+v", schema)
+	}
+	if endpoint := schema.Endpoints["DELETE /users/{id}"]; endpoint.Path != "/users/{id}" || endpoint.Type != "DELETE" {
+		t.Fatalf("unexpected endpoint %+v", endpoint)
+	}
+	if !schema.Endpoints["createUser"].Body["email"].Required {
+		t.Fatal("expected the email to be required")
+	}
+}
+
+func TestExportOpenApi(t *testing.T) {
+	// complete is true when the exported document is translated without a report
+	for file, complete := range map[string]bool{
+		"../../test-data/schema/api/openapi/example.json": true,
+		"../../test-data/schema/api/openapi/nested.json":  false,
+	} {
+		schema, _, err := LoadOpenApiFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		exported, err := schema.ExportOpenApi("Export")
+		if err != nil {
+			t.Fatal(err)
+		}
+		reloaded, report, err := LoadOpenApi(exported)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if complete && len(report) > 0 {
+			t.Fatalf("%s: expected the exported document to be translated completely, got %v", file, report)
+		}
+		if !reflect.DeepEqual(schema.Endpoints, reloaded.Endpoints) {
+			t.Fatalf("%s: expected the endpoints to survive the round trip\n%+v\ngot\n%+v", file, schema.Endpoints, reloaded.Endpoints)
+		}
+	}
+}
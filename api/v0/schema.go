@@ -1,6 +1,7 @@
 package v0
 
 import (
+	"encoding/json"
 	"github.com/ashbeelghouri/jsonschematics/api/parsers"
 	jsonschematics "github.com/ashbeelghouri/jsonschematics/data/v0"
 	"github.com/ashbeelghouri/jsonschematics/errorHandler"
@@ -15,13 +16,32 @@ type EndpointKey string
 type Name string
 
 type Field struct {
-	DependsOn  []string
-	Name       string
-	Type       string
-	Required   bool
-	Validators map[TargetKey]Constant
-	Operators  map[TargetKey]Constant
-	L10n       map[string]interface{}
+	DependsOn  []string               `json:"depends_on"`
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Required   bool                   `json:"required"`
+	Validators map[TargetKey]Constant `json:"validators"`
+	Operators  map[TargetKey]Constant `json:"operators"`
+	L10n       map[string]interface{} `json:"l10n"`
+	Fields     map[TargetKey]Field    `json:"fields"`
+	Items      *Field                 `json:"items"`
+}
+
+// UnmarshalJSON also reads the "DependsOn" key of the files written before it was "depends_on"
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	var decoded struct {
+		field
+		LegacyDependsOn []string `json:"DependsOn"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = Field(decoded.field)
+	if f.DependsOn == nil {
+		f.DependsOn = decoded.LegacyDependsOn
+	}
+	return nil
 }
 
 type Constant struct {
@@ -31,67 +51,102 @@ type Constant struct {
 }
 
 type Global struct {
-	Headers map[TargetKey]Field
+	Headers map[TargetKey]Field `json:"headers"`
 }
 
+// Endpoint is matched by its Path, or by its key in the endpoints when it has no path, and by its method in Type
 type Endpoint struct {
-	Type    string
-	Body    map[TargetKey]Field
-	Headers map[TargetKey]Field
-	Query   map[TargetKey]Field
+	Path    string              `json:"path"`
+	Type    string              `json:"type"`
+	Body    map[TargetKey]Field `json:"body"`
+	Headers map[TargetKey]Field `json:"headers"`
+	Query   map[TargetKey]Field `json:"query"`
 }
 
 type Schema struct {
-	Version   string
-	Global    Global
-	Locale    string
-	Logger    utils.Logger
-	Endpoints map[EndpointKey]Endpoint
+	Version   string                   `json:"version"`
+	Global    Global                   `json:"global"`
+	Locale    string                   `json:"locale"`
+	Logger    utils.Logger             `json:"-"`
+	Endpoints map[EndpointKey]Endpoint `json:"endpoints"`
+}
+
+// constantL10n reads the "name" and "error" translations from the l10n of the constant
+func constantL10n(l10n map[string]interface{}) jsonschematics.ConstantL10n {
+	var c jsonschematics.ConstantL10n
+	if name, ok := l10n["name"].(map[string]interface{}); ok {
+		c.Name = name
+	}
+	if err, ok := l10n["error"].(map[string]interface{}); ok {
+		c.Error = err
+	}
+	return c
 }
 
 func (s *Schema) GetSchematics(fieldType string, fields *map[TargetKey]Field) (*jsonschematics.Schematics, error) {
 	var schematics jsonschematics.Schematics
-	FieldKeys := jsonschematics.Field{
-		DependsOn:  []string{},
-		Type:       fieldType,
-		Validators: map[string]jsonschematics.Constant{},
-		Operators:  map[string]jsonschematics.Constant{},
-	}
+	schematics.Logging = s.Logger
+	schematics.Validators.BasicValidators()
+	schematics.Operators.LoadBasicOperations()
+	schematics.Conditions.BasicConditions()
+	schematics.Separator = "."
+	schematics.Locale = "en"
 
 	schema := jsonschematics.Schema{
 		Version: s.Version,
 		Fields:  make(map[jsonschematics.TargetKey]jsonschematics.Field),
 	}
 
+	// headers and query parameters are always strings, they are converted to the type of the field
 	for target, f := range *fields {
-		var allValidators map[string]jsonschematics.Constant
-
-		for key, validator := range f.Validators {
-			allValidators[string(key)] = jsonschematics.Constant{
-				Attributes: validator.Attributes,
-				Error:      validator.ErrMsg,
-				L10n:       validator.L10n,
-			}
-		}
-		var allOperations map[string]jsonschematics.Constant
-		for key, operator := range f.Operators {
-			allOperations[string(key)] = jsonschematics.Constant{
-				Attributes: operator.Attributes,
-				Error:      operator.ErrMsg,
-				L10n:       operator.L10n,
-			}
-		}
-		FieldKeys.Type = f.Type
-		FieldKeys.Validators = allValidators
-		FieldKeys.Operators = allOperations
-		FieldKeys.L10n = s.Global.Headers[target].L10n
-		schema.Fields[jsonschematics.TargetKey(target)] = FieldKeys
+		schema.Fields[jsonschematics.TargetKey(target)] = f.schematicsField(fieldType != "Body")
 	}
 
 	schematics.Schema = schema
 	return &schematics, nil
 }
 
+// schematicsField converts the field with its nested fields and items to the field of the schematics
+func (f Field) schematicsField(coerce bool) jsonschematics.Field {
+	allValidators := make(map[string]jsonschematics.Constant)
+	for key, validator := range f.Validators {
+		allValidators[string(key)] = jsonschematics.Constant{
+			Attributes: validator.Attributes,
+			Error:      validator.ErrMsg,
+			L10n:       constantL10n(validator.L10n),
+		}
+	}
+	allOperations := make(map[string]jsonschematics.Constant)
+	for key, operator := range f.Operators {
+		allOperations[string(key)] = jsonschematics.Constant{
+			Attributes: operator.Attributes,
+			Error:      operator.ErrMsg,
+			L10n:       constantL10n(operator.L10n),
+		}
+	}
+	field := jsonschematics.Field{
+		DependsOn:  f.DependsOn,
+		Name:       f.Name,
+		Type:       f.Type,
+		IsRequired: f.Required,
+		Coerce:     coerce,
+		Validators: allValidators,
+		Operators:  allOperations,
+		L10n:       f.L10n,
+	}
+	if len(f.Fields) > 0 {
+		field.Fields = make(map[jsonschematics.TargetKey]jsonschematics.Field, len(f.Fields))
+		for target, nested := range f.Fields {
+			field.Fields[jsonschematics.TargetKey(target)] = nested.schematicsField(coerce)
+		}
+	}
+	if f.Items != nil {
+		items := f.Items.schematicsField(coerce)
+		field.Items = &items
+	}
+	return field
+}
+
 func (s *Schema) ValidateRequest(r *http.Request) *errorHandler.Errors {
 	internalErrors := "internal-errors"
 
@@ -116,21 +171,27 @@ func (s *Schema) ValidateRequest(r *http.Request) *errorHandler.Errors {
 	}
 	errs := globalHeadersSchematics.Validate(transformedRequest["headers"])
 	if errs.HasErrors() {
-		s.Logger.ERROR("all errors", err.Error())
+		s.Logger.ERROR("validation errors on global headers:", errs.GetStrings("en", "%validator: %message"))
 		return errs
 	}
 
-	for path, endpoint := range s.Endpoints {
-		regex := utils.GetPathRegex(string(path))
+	// the endpoints are checked in the order of their keys so that the same request always reports the same errors
+	for _, key := range sortedEndpoints(s.Endpoints) {
+		endpoint := s.Endpoints[key]
+		path := endpoint.Path
+		if path == "" {
+			path = string(key)
+		}
+		regex := utils.GetPathRegex(path)
 		matched, err := regexp.MatchString(regex, transformedRequest["path"].(string))
 		if err != nil {
 			errMsg.AddMessage("en", "path not matched - regex not matched")
 			errorMessages.AddError(internalErrors, errMsg)
 			return &errorMessages
 		}
-		if matched {
+		if !matched {
 			s.Logger.DEBUG("url not matched")
-			return nil
+			continue
 		}
 
 		if strings.ToLower(endpoint.Type) == strings.ToLower(transformedRequest["method"].(string)) {
@@ -0,0 +1,78 @@
+package v0
+
+import (
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// requestCase is a request and the targets it should have errors on, none when errors is empty
+type requestCase struct {
+	method string
+	target string
+	header map[string]string
+	body   string
+	errors []string
+}
+
+func runRequestCases(t *testing.T, schema *Schema, cases []requestCase) {
+	t.Helper()
+	for _, test := range cases {
+		r := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+		for key, value := range test.header {
+			r.Header.Set(key, value)
+		}
+		errs := schema.ValidateRequest(r)
+		if len(test.errors) == 0 && errs != nil && errs.HasErrors() {
+			t.Errorf("%s %s: expected no errors, got %v", test.method, test.target, errs.GetStrings("en", "%target: %message"))
+		}
+		for _, target := range test.errors {
+			if errs == nil || !hasErrorOn(errs, target) {
+				t.Errorf("%s %s %s: expected an error on %s", test.method, test.target, test.body, target)
+			}
+		}
+	}
+}
+
+func hasErrorOn(errs *errorHandler.Errors, target string) bool {
+	_, exists := errs.Messages[errorHandler.Target(target)]
+	return exists
+}
+
+func TestValidateRequest(t *testing.T) {
+	schema := Schema{
+		Version: "1",
+		Endpoints: map[EndpointKey]Endpoint{
+			"/health": {Type: "GET"},
+			"/users/:id": {Type: "GET", Query: map[TargetKey]Field{
+				"limit": {Type: "number", Validators: map[TargetKey]Constant{"MaxAllowed": {Attributes: map[string]interface{}{"max": 100}}}},
+			}},
+			"/users/:id/orders": {Type: "POST", Body: map[TargetKey]Field{
+				"item": {Required: true, Validators: map[TargetKey]Constant{"IsString": {}}},
+			}},
+		},
+	}
+	runRequestCases(t, &schema, []requestCase{
+		{method: "GET", target: "/health"},
+		// the query values are strings, they are converted to the type of the field
+		{method: "GET", target: "/users/7?limit=5"},
+		{method: "GET", target: "/users/7?limit=500", errors: []string{"limit"}},
+		{method: "POST", target: "/users/7/orders", body: `{}`, errors: []string{"item"}},
+		{method: "POST", target: "/users/7/orders", body: `{"item": "book"}`},
+	})
+}
+
+func TestValidateRequestEndpointsOrder(t *testing.T) {
+	schema := Schema{
+		Version: "1",
+		Endpoints: map[EndpointKey]Endpoint{
+			"/items/:slug": {Type: "GET", Query: map[TargetKey]Field{"slug": {Required: true}}},
+			"/items/:id":   {Type: "GET", Query: map[TargetKey]Field{"id": {Required: true}}},
+		},
+	}
+	// both endpoints match, the first one in the order of the keys reports its errors
+	for i := 0; i < 20; i++ {
+		runRequestCases(t, &schema, []requestCase{{method: "GET", target: "/items/7", errors: []string{"id"}}})
+	}
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"encoding/json"
 	basic "github.com/ashbeelghouri/jsonschematics/api/v0"
+	"github.com/ashbeelghouri/jsonschematics/metaschema"
 	"github.com/ashbeelghouri/jsonschematics/utils"
 	"log"
 	"os"
@@ -31,7 +32,7 @@ type Endpoint struct {
 }
 
 type Field struct {
-	DependsOn             []string
+	DependsOn             []string               `json:"depends_on"`
 	Key                   string                 `json:"target_key"`
 	Validators            map[string]Constant    `json:"validators"`
 	Operators             map[string]Constant    `json:"operators"`
@@ -39,6 +40,23 @@ type Field struct {
 	AdditionalInformation map[string]interface{} `json:"additional_information"`
 }
 
+// UnmarshalJSON also reads the "DependsOn" key of the files written before it was "depends_on"
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	var decoded struct {
+		field
+		LegacyDependsOn []string `json:"DependsOn"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = Field(decoded.field)
+	if f.DependsOn == nil {
+		f.DependsOn = decoded.LegacyDependsOn
+	}
+	return nil
+}
+
 type Constant struct {
 	Attributes map[string]interface{} `json:"attributes"`
 	ErrMsg     string                 `json:"error"`
@@ -63,6 +81,10 @@ func LoadJsonSchemaFile(path string) (*basic.Schema, error) {
 		Logs.ERROR("Failed to load schema file", err)
 		return nil, err
 	}
+	if err := metaschema.Validate("api/v1", content); err != nil {
+		Logs.ERROR("Invalid schema file", err)
+		return nil, err
+	}
 	err = json.Unmarshal(content, &schema)
 	if err != nil {
 		return nil, err
@@ -71,13 +93,17 @@ func LoadJsonSchemaFile(path string) (*basic.Schema, error) {
 }
 
 func LoadMap(schemaMap interface{}) (*basic.Schema, error) {
-	var s *Schema
+	var s Schema
 	s.Configs()
 	jsonBytes, err := json.Marshal(schemaMap)
 	if err != nil {
 		Logs.ERROR("Schema should be valid json map[string]interface", err)
 		return nil, err
 	}
+	if err := metaschema.Validate("api/v1", jsonBytes); err != nil {
+		Logs.ERROR("Invalid schema", err)
+		return nil, err
+	}
 	err = json.Unmarshal(jsonBytes, &s)
 	if err != nil {
 		return nil, err
@@ -135,7 +161,7 @@ func (s *Schema) transformTov0() *basic.Schema {
 		}
 
 		query := map[basic.TargetKey]basic.Field{}
-		for _, field := range endpoint.Body {
+		for _, field := range endpoint.Query {
 			query[basic.TargetKey(field.Key)] = basic.Field{
 				DependsOn:  field.DependsOn,
 				Validators: transformComponents(field.Validators),
@@ -145,6 +171,7 @@ func (s *Schema) transformTov0() *basic.Schema {
 		}
 
 		endpoints[basic.EndpointKey(path)] = basic.Endpoint{
+			Path:    endpoint.Path,
 			Type:    endpoint.Type,
 			Body:    body,
 			Headers: headers,
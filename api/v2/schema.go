@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	basic "github.com/ashbeelghouri/jsonschematics/api/v0"
 	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"github.com/ashbeelghouri/jsonschematics/metaschema"
 	"github.com/ashbeelghouri/jsonschematics/utils"
 	"log"
 	"net/http"
@@ -33,7 +34,7 @@ type Endpoint struct {
 }
 
 type Field struct {
-	DependsOn             []string
+	DependsOn             []string               `json:"depends_on"`
 	Key                   string                 `json:"target_key"`
 	Validators            []Component            `json:"validators"`
 	Operators             []Component            `json:"operators"`
@@ -41,11 +42,45 @@ type Field struct {
 	AdditionalInformation map[string]interface{} `json:"additional_information"`
 }
 
+// UnmarshalJSON also reads the "DependsOn" key of the files written before it was "depends_on"
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	var decoded struct {
+		field
+		LegacyDependsOn []string `json:"DependsOn"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = Field(decoded.field)
+	if f.DependsOn == nil {
+		f.DependsOn = decoded.LegacyDependsOn
+	}
+	return nil
+}
+
 type Component struct {
-	Name       string
-	Attributes map[string]interface{}
-	ErrMsg     string
-	L10n       map[string]interface{}
+	Name       string                 `json:"name"`
+	Attributes map[string]interface{} `json:"attributes"`
+	ErrMsg     string                 `json:"error"`
+	L10n       map[string]interface{} `json:"l10n"`
+}
+
+// UnmarshalJSON also reads the "ErrMsg" key of the files written before it was "error"
+func (c *Component) UnmarshalJSON(data []byte) error {
+	type component Component
+	var decoded struct {
+		component
+		LegacyErrMsg string `json:"ErrMsg"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*c = Component(decoded.component)
+	if c.ErrMsg == "" {
+		c.ErrMsg = decoded.LegacyErrMsg
+	}
+	return nil
 }
 
 func (s *Schema) Configs() {
@@ -66,6 +101,10 @@ func LoadJsonSchemaFile(path string) (*basic.Schema, error) {
 		Logs.ERROR("Failed to load schema file", err)
 		return nil, err
 	}
+	if err := metaschema.Validate("api/v2", content); err != nil {
+		Logs.ERROR("Invalid schema file", err)
+		return nil, err
+	}
 	err = json.Unmarshal(content, &schema)
 	if err != nil {
 		return nil, err
@@ -74,13 +113,17 @@ func LoadJsonSchemaFile(path string) (*basic.Schema, error) {
 }
 
 func LoadMap(schemaMap interface{}) (*basic.Schema, error) {
-	var s *Schema
+	var s Schema
 	s.Configs()
 	jsonBytes, err := json.Marshal(schemaMap)
 	if err != nil {
 		Logs.ERROR("Schema should be valid json map[string]interface", err)
 		return nil, err
 	}
+	if err := metaschema.Validate("api/v2", jsonBytes); err != nil {
+		Logs.ERROR("Invalid schema", err)
+		return nil, err
+	}
 	err = json.Unmarshal(jsonBytes, &s)
 	if err != nil {
 		return nil, err
@@ -138,7 +181,7 @@ func (s *Schema) transformTov0() *basic.Schema {
 		}
 
 		query := map[basic.TargetKey]basic.Field{}
-		for _, field := range endpoint.Body {
+		for _, field := range endpoint.Query {
 			query[basic.TargetKey(field.Key)] = basic.Field{
 				DependsOn:  field.DependsOn,
 				Validators: transformComponents(field.Validators),
@@ -147,6 +190,7 @@ func (s *Schema) transformTov0() *basic.Schema {
 			}
 		}
 		endpoints[basic.EndpointKey(path)] = basic.Endpoint{
+			Path:    endpoint.Path,
 			Type:    endpoint.Type,
 			Body:    body,
 			Headers: headers,
@@ -162,3 +206,13 @@ func (s *Schema) ValidateRequest(r *http.Request) *errorHandler.Errors {
 	baseSchema := s.transformTov0()
 	return baseSchema.ValidateRequest(r)
 }
+
+// OpenApi converts the schema to an OpenAPI document with the title, see OpenApi of api/v0
+func (s *Schema) OpenApi(title string) map[string]interface{} {
+	return s.transformTov0().OpenApi(title)
+}
+
+// ExportOpenApi returns the OpenApi document of the schema as indented JSON
+func (s *Schema) ExportOpenApi(title string) ([]byte, error) {
+	return s.transformTov0().ExportOpenApi(title)
+}
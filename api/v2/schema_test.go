@@ -0,0 +1,65 @@
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOpenApi(t *testing.T) {
+	schema := Schema{
+		Version: "2.0",
+		Global:  Global{Headers: []Field{{Key: "Authorization", Validators: []Component{{Name: "LIKE", Attributes: map[string]interface{}{"pattern": "Bearer %"}}}}}},
+		Endpoints: map[string]Endpoint{"updateUser": {
+			Path:  "/users/:id",
+			Type:  "patch",
+			Query: []Field{{Key: "dryRun", Validators: []Component{{Name: "StringInOptions", Attributes: map[string]interface{}{"options": []interface{}{"true", "false"}}}}}},
+			Body: []Field{
+				{Key: "name", Validators: []Component{{Name: "MaxLengthAllowed", Attributes: map[string]interface{}{"max": 20}}}},
+				{Key: "address.zip", Validators: []Component{{Name: "IsPostalCode"}}},
+			},
+		}},
+	}
+	document, err := json.Marshal(schema.OpenApi("Users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"info":{"title":"Users","version":"2.0"},"openapi":"3.1.0","paths":{"/users/{id}":{"patch":{` +
+		`"operationId":"updateUser","parameters":[` +
+		`{"in":"path","name":"id","required":true,"schema":{"type":"string"}},` +
+		`{"in":"header","name":"Authorization","schema":{"pattern":"^Bearer .*$","type":"string"}},` +
+		`{"in":"query","name":"dryRun","schema":{"enum":["true","false"]}}],` +
+		`"requestBody":{"content":{"application/json":{"schema":{"properties":{` +
+		`"address":{"properties":{"zip":{"x-validators":[{"name":"IsPostalCode"}]}},"type":"object"},` +
+		`"name":{"maxLength":20}},"type":"object"}}}}}}}}`
+	if string(document) != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, document)
+	}
+}
+
+func TestLoadLegacyKeys(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"current": {"target_key": "name", "depends_on": []interface{}{"id"}, "validators": []interface{}{
+			map[string]interface{}{"name": "IsString", "error": "the name should be a string"},
+		}},
+		// the keys of the files written before the json tags were added
+		"legacy": {"target_key": "name", "DependsOn": []interface{}{"id"}, "validators": []interface{}{
+			map[string]interface{}{"Name": "IsString", "ErrMsg": "the name should be a string"},
+		}},
+	}
+	for name, field := range tests {
+		schema, err := LoadMap(map[string]interface{}{
+			"version":   "2",
+			"endpoints": map[string]interface{}{"/users": map[string]interface{}{"type": "POST", "body": []interface{}{field}}},
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		loaded := schema.Endpoints["/users"].Body["name"]
+		if message := loaded.Validators["IsString"].ErrMsg; message != "the name should be a string" {
+			t.Errorf("%s: expected the error message to be loaded, got %q", name, message)
+		}
+		if len(loaded.DependsOn) != 1 || loaded.DependsOn[0] != "id" {
+			t.Errorf("%s: expected the dependencies to be loaded, got %v", name, loaded.DependsOn)
+		}
+	}
+}
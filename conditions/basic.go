@@ -1,20 +1,107 @@
 package conditions
 
 import (
-	"github.com/ashbeelghouri/jsonschematics/structures"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"regexp"
 )
 
-func FieldIsProvided(_ map[string]interface{}, attr map[string]interface{}) bool {
-	fields, ok := attr["schema"].(structures.Schema)
-	if !ok {
-		return false
+// documentOf returns the flat document and its separator from the attributes of the condition
+func documentOf(attr map[string]interface{}) (map[string]interface{}, string) {
+	document, _ := attr["document"].(map[string]interface{})
+	separator, _ := attr["separator"].(string)
+	if separator == "" {
+		separator = "."
 	}
+	return document, separator
+}
+
+// fieldOf returns the value of the target in the "field" attribute, exists is false when it is not in the document
+func fieldOf(attr map[string]interface{}) (value interface{}, exists bool) {
+	target, ok := attr["field"].(string)
+	if !ok || target == "" {
+		return nil, false
+	}
+	document, separator := documentOf(attr)
+	return lookup(document, target, separator)
+}
+
+// FieldIsProvided passes when the target in the "shouldBeProvided" attribute is in the document
+func FieldIsProvided(_ map[string]interface{}, attr map[string]interface{}) bool {
 	toBeProvided, ok := attr["shouldBeProvided"].(string)
 	if !ok {
 		return false
 	}
-	if fields.Fields[(structures.TargetKey(toBeProvided))].Provided {
+	document, separator := documentOf(attr)
+	if _, exists := document[toBeProvided]; exists {
 		return true
 	}
+	return len(utils.FindMatchingKeys(document, toBeProvided, separator)) > 0
+}
+
+// FieldEquals passes when the "field" is equal to the "value" attribute
+func FieldEquals(_ map[string]interface{}, attr map[string]interface{}) bool {
+	value, exists := fieldOf(attr)
+	return exists && equal(value, attr["value"])
+}
+
+// FieldIn passes when the "field" is one of the "values" attribute
+func FieldIn(_ map[string]interface{}, attr map[string]interface{}) bool {
+	value, exists := fieldOf(attr)
+	if !exists {
+		return false
+	}
+	values, _ := attr["values"].([]interface{})
+	for _, option := range values {
+		if equal(value, option) {
+			return true
+		}
+	}
 	return false
 }
+
+// FieldMatchesRegex passes when the "field" is a string that matches the "regex" attribute
+func FieldMatchesRegex(_ map[string]interface{}, attr map[string]interface{}) bool {
+	value, exists := fieldOf(attr)
+	str, isString := value.(string)
+	pattern, hasPattern := attr["regex"].(string)
+	if !exists || !isString || !hasPattern {
+		return false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(str)
+}
+
+// FieldGreaterThan passes when the "field" is greater than the "value" attribute, or equal to it when "or_equal" is true
+func FieldGreaterThan(_ map[string]interface{}, attr map[string]interface{}) bool {
+	value, exists := fieldOf(attr)
+	if !exists {
+		return false
+	}
+	order, ok := compare(value, attr["value"])
+	orEqual, _ := attr["or_equal"].(bool)
+	return ok && (order > 0 || (order == 0 && orEqual))
+}
+
+// FieldIsEmpty passes when the "field" is not provided, null, or an empty string, array or object
+func FieldIsEmpty(_ map[string]interface{}, attr map[string]interface{}) bool {
+	value, exists := fieldOf(attr)
+	if !exists || value == nil {
+		return true
+	}
+	if _, isBool := value.(bool); isBool {
+		return false
+	}
+	if _, isNumber := toNumber(value); isNumber {
+		return false
+	}
+	return !truthy(value)
+}
+
+// FieldIsMissing passes when the "field" is not in the document
+func FieldIsMissing(_ map[string]interface{}, attr map[string]interface{}) bool {
+	_, exists := fieldOf(attr)
+	return !exists
+}
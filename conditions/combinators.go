@@ -0,0 +1,99 @@
+package conditions
+
+import (
+	"sort"
+)
+
+// Nested is a condition declared in the "conditions" attribute of AllOf, AnyOf and Not
+type Nested struct {
+	Name       string
+	Attributes map[string]interface{}
+}
+
+// NestedConditions returns the conditions in the "conditions" attribute, either a list like in v2
+// [{"name": "FieldEquals", "attributes": {...}}] or a map like in v0 {"FieldEquals": {"attributes": {...}}}
+func NestedConditions(attr map[string]interface{}) []Nested {
+	var nested []Nested
+	switch declared := attr["conditions"].(type) {
+	case []interface{}:
+		for _, item := range declared {
+			condition, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := condition["name"].(string)
+			attributes, _ := condition["attributes"].(map[string]interface{})
+			nested = append(nested, Nested{Name: name, Attributes: attributes})
+		}
+	case map[string]interface{}:
+		names := make([]string, 0, len(declared))
+		for name := range declared {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			condition, _ := declared[name].(map[string]interface{})
+			attributes, _ := condition["attributes"].(map[string]interface{})
+			nested = append(nested, Nested{Name: name, Attributes: attributes})
+		}
+	}
+	return nested
+}
+
+// Unregistered returns the name and the nested names of the condition that are not registered
+func (c *Conditions) Unregistered(name string, attr map[string]interface{}) []string {
+	if _, ok := c.ConditionFns[name]; !ok {
+		return []string{name}
+	}
+	var missing []string
+	if name == "AllOf" || name == "AnyOf" || name == "Not" {
+		for _, nested := range NestedConditions(attr) {
+			missing = append(missing, c.Unregistered(nested.Name, nested.Attributes)...)
+		}
+	}
+	return missing
+}
+
+// evaluate runs the nested condition with the document of the parent condition, an unregistered condition does not pass
+func (c *Conditions) evaluate(field map[string]interface{}, nested Nested, parent map[string]interface{}) bool {
+	fn, ok := c.ConditionFns[nested.Name]
+	if !ok {
+		c.Logger.ERROR("condition is not registered:", nested.Name)
+		return false
+	}
+	attrs := make(map[string]interface{}, len(nested.Attributes)+3)
+	for key, value := range nested.Attributes {
+		attrs[key] = value
+	}
+	for _, key := range []string{"schema", "document", "separator"} {
+		if value, exists := parent[key]; exists {
+			attrs[key] = value
+		}
+	}
+	return fn(field, attrs)
+}
+
+// AllOf passes when all the nested conditions pass
+func (c *Conditions) AllOf(field map[string]interface{}, attr map[string]interface{}) bool {
+	for _, nested := range NestedConditions(attr) {
+		if !c.evaluate(field, nested, attr) {
+			return false
+		}
+	}
+	return true
+}
+
+// AnyOf passes when at least one of the nested conditions passes
+func (c *Conditions) AnyOf(field map[string]interface{}, attr map[string]interface{}) bool {
+	for _, nested := range NestedConditions(attr) {
+		if c.evaluate(field, nested, attr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not passes when the nested conditions do not all pass, with a single nested condition it is the opposite of it
+func (c *Conditions) Not(field map[string]interface{}, attr map[string]interface{}) bool {
+	return !c.AllOf(field, attr)
+}
@@ -0,0 +1,435 @@
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Expression is a parsed condition over the document, e.g. country == "US" && age >= 18.
+// It supports the literals "text", 'text', numbers, true, false, null and lists like ["US", "CA"], the operators
+// == != < <= > >= in && || ! and parentheses. Any other name is a target in the document, parts are separated with dots
+// (items.0.name) and a target that is not in the document is null
+type Expression struct {
+	source string
+	root   expressionNode
+}
+
+// ParseExpression parses the expression, the error tells where the expression is not valid
+func ParseExpression(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := expressionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEnd {
+		err = fmt.Errorf("unexpected %s at %d", p.peek().text, p.peek().position)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate tells if the expression is true for the flat document, separator is the separator of the flat keys
+func (e *Expression) Evaluate(document map[string]interface{}, separator string) bool {
+	if separator == "" {
+		separator = "."
+	}
+	return truthy(e.root.eval(func(target string) interface{} {
+		value, _ := lookup(document, strings.ReplaceAll(target, ".", separator), separator)
+		return value
+	}))
+}
+
+// lookup returns the value of the target in the flat document, nested objects and arrays are returned as a whole
+func lookup(document map[string]interface{}, target string, separator string) (interface{}, bool) {
+	if value, exists := document[target]; exists {
+		return value, true
+	}
+	matchingKeys := utils.FindMatchingKeys(document, target, separator)
+	if value, exists := matchingKeys[target]; exists {
+		return value, true
+	}
+	if len(matchingKeys) == 1 {
+		return utils.GetFirstFromMap(matchingKeys), true
+	}
+	return nil, false
+}
+
+// parsedExpressions are the results of ParseExpression by the source, so the expression of a condition is only parsed
+// the first time it is evaluated, like the when of a field is parsed once when it is compiled
+var parsedExpressions sync.Map
+
+type parsedExpression struct {
+	expression *Expression
+	err        error
+}
+
+// ExpressionCondition is the condition that evaluates the "expression" attribute over the "document" attribute
+func ExpressionCondition(_ map[string]interface{}, attr map[string]interface{}) bool {
+	source, ok := attr["expression"].(string)
+	if !ok {
+		return false
+	}
+	expression, err := cachedExpression(source)
+	if err != nil {
+		return false
+	}
+	document, separator := documentOf(attr)
+	return expression.Evaluate(document, separator)
+}
+
+// cachedExpression returns the parsed expression, or the parse error, of the source from parsedExpressions
+func cachedExpression(source string) (*Expression, error) {
+	if cached, ok := parsedExpressions.Load(source); ok {
+		parsed := cached.(parsedExpression)
+		return parsed.expression, parsed.err
+	}
+	expression, err := ParseExpression(source)
+	parsedExpressions.Store(source, parsedExpression{expression: expression, err: err})
+	return expression, err
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("string at %d is not closed", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: sb.String(), position: start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), position: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, text: string(runes[start:i]), position: start})
+		default:
+			operator := ""
+			for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operator, position: i})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, text: "end of expression", position: len(runes)}), nil
+}
+
+type expressionNode interface {
+	eval(get func(string) interface{}) interface{}
+}
+
+type literalNode struct{ value interface{} }
+
+type targetNode struct{ target string }
+
+type listNode struct{ items []expressionNode }
+
+type notNode struct{ operand expressionNode }
+
+type binaryNode struct {
+	operator    string
+	left, right expressionNode
+}
+
+func (n literalNode) eval(_ func(string) interface{}) interface{} {
+	return n.value
+}
+
+func (n targetNode) eval(get func(string) interface{}) interface{} {
+	return get(n.target)
+}
+
+func (n listNode) eval(get func(string) interface{}) interface{} {
+	values := make([]interface{}, len(n.items))
+	for i, item := range n.items {
+		values[i] = item.eval(get)
+	}
+	return values
+}
+
+func (n notNode) eval(get func(string) interface{}) interface{} {
+	return !truthy(n.operand.eval(get))
+}
+
+func (n binaryNode) eval(get func(string) interface{}) interface{} {
+	switch n.operator {
+	case "&&":
+		return truthy(n.left.eval(get)) && truthy(n.right.eval(get))
+	case "||":
+		return truthy(n.left.eval(get)) || truthy(n.right.eval(get))
+	}
+	left, right := n.left.eval(get), n.right.eval(get)
+	switch n.operator {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "in":
+		list := reflect.ValueOf(right)
+		if right == nil || (list.Kind() != reflect.Slice && list.Kind() != reflect.Array) {
+			return false
+		}
+		for i := 0; i < list.Len(); i++ {
+			if equal(left, list.Index(i).Interface()) {
+				return true
+			}
+		}
+		return false
+	}
+	order, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch n.operator {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
+
+type expressionParser struct {
+	tokens   []token
+	position int
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.position]
+	if t.kind != tokenEnd {
+		p.position++
+	}
+	return t
+}
+
+func (p *expressionParser) accept(operator string) bool {
+	if t := p.peek(); (t.kind == tokenOperator || t.kind == tokenIdentifier) && t.text == operator {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) expect(operator string) error {
+	if !p.accept(operator) {
+		return fmt.Errorf("expected %s at %d", operator, p.peek().position)
+	}
+	return nil
+}
+
+func (p *expressionParser) parseOr() (expressionNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("||") {
+		var right expressionNode
+		right, err = p.parseAnd()
+		left = binaryNode{operator: "||", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *expressionParser) parseAnd() (expressionNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept("&&") {
+		var right expressionNode
+		right, err = p.parseNot()
+		left = binaryNode{operator: "&&", left: left, right: right}
+	}
+	return left, err
+}
+
+func (p *expressionParser) parseNot() (expressionNode, error) {
+	if p.accept("!") {
+		operand, err := p.parseNot()
+		return notNode{operand: operand}, err
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (expressionNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(operator) {
+			right, err := p.parsePrimary()
+			return binaryNode{operator: operator, left: left, right: right}, err
+		}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parsePrimary() (expressionNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literalNode{value: t.text}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at %d", t.text, t.position)
+		}
+		return literalNode{value: number}, nil
+	case tokenIdentifier:
+		switch t.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null":
+			return literalNode{value: nil}, nil
+		case "in":
+			return nil, fmt.Errorf("unexpected in at %d", t.position)
+		}
+		return targetNode{target: t.text}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		case "[":
+			var list listNode
+			if p.accept("]") {
+				return list, nil
+			}
+			for {
+				item, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if !p.accept(",") {
+					return list, p.expect("]")
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at %d", t.text, t.position)
+}
+
+func truthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	if number, ok := toNumber(value); ok {
+		return number != 0
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() > 0
+	}
+	return true
+}
+
+func equal(a interface{}, b interface{}) bool {
+	numberA, okA := toNumber(a)
+	numberB, okB := toNumber(b)
+	if okA && okB {
+		return numberA == numberB
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders two numbers or two strings, ok is false when the values can not be ordered
+func compare(a interface{}, b interface{}) (int, bool) {
+	numberA, okA := toNumber(a)
+	numberB, okB := toNumber(b)
+	if okA && okB {
+		switch {
+		case numberA < numberB:
+			return -1, true
+		case numberA > numberB:
+			return 1, true
+		}
+		return 0, true
+	}
+	stringA, okA := a.(string)
+	stringB, okB := b.(string)
+	if okA && okB {
+		return strings.Compare(stringA, stringB), true
+	}
+	return 0, false
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		f, err := number.Float64()
+		return f, err == nil
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
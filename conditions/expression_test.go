@@ -0,0 +1,105 @@
+package conditions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpressions(t *testing.T) {
+	document := map[string]interface{}{
+		"country":       "US",
+		"age":           21,
+		"tags.0":        "skip",
+		"address.city":  "Austin",
+		"address.zip":   nil,
+		"score":         9.5,
+		"user.verified": true,
+	}
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`country == "US" && age >= 18`, true},
+		{`country == 'US' && age < 18`, false},
+		{`country in ["US", "CA"] && !(tags.0 == 'skip')`, false},
+		{`country != "US" || user.verified`, true},
+		{`address.zip == null && missing == null`, true},
+		{`score > 9`, true},
+		{`(age > 30 || country == "CA") && true`, false},
+	}
+	for _, test := range tests {
+		expression, err := ParseExpression(test.expression)
+		if err != nil {
+			t.Fatalf("%s: %v", test.expression, err)
+		}
+		if got := expression.Evaluate(document, "."); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.expression, test.expected, got)
+		}
+	}
+
+	for _, invalid := range []string{`country == `, `age >= 18)`, `"US`, `country = "US"`} {
+		if _, err := ParseExpression(invalid); err == nil || !strings.Contains(err.Error(), "invalid expression") {
+			t.Errorf("%s: expected the expression not to be valid, got %v", invalid, err)
+		}
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	var c Conditions
+	c.BasicConditions()
+	document := map[string]interface{}{"country": "DE", "company": "Acme GmbH"}
+	tests := []struct {
+		name       string
+		conditions interface{}
+		expected   bool
+	}{
+		{"AllOf", []interface{}{
+			map[string]interface{}{"name": "FieldEquals", "attributes": map[string]interface{}{"field": "country", "value": "DE"}},
+			map[string]interface{}{"name": "FieldMatchesRegex", "attributes": map[string]interface{}{"field": "company", "regex": "(?i)gmbh$"}},
+		}, true},
+		{"AnyOf", map[string]interface{}{
+			"FieldIn":        map[string]interface{}{"attributes": map[string]interface{}{"field": "country", "values": []interface{}{"US", "CA"}}},
+			"FieldIsMissing": map[string]interface{}{"attributes": map[string]interface{}{"field": "company"}},
+		}, false},
+		{"Not", []interface{}{
+			map[string]interface{}{"name": "FieldIsEmpty", "attributes": map[string]interface{}{"field": "company"}},
+		}, true},
+		{"AllOf", []interface{}{
+			map[string]interface{}{"name": "FieldIsGreen"},
+		}, false},
+	}
+	for i, test := range tests {
+		attr := map[string]interface{}{"conditions": test.conditions, "document": document}
+		if got := c.ConditionFns[test.name](nil, attr); got != test.expected {
+			t.Errorf("%d %s: expected %v, got %v", i, test.name, test.expected, got)
+		}
+	}
+}
+
+func TestExpressionConditionParsesOnce(t *testing.T) {
+	const source = `status == "active" && age >= 18`
+	tests := []struct {
+		document map[string]interface{}
+		expected bool
+	}{
+		{map[string]interface{}{"status": "active", "age": 20}, true},
+		{map[string]interface{}{"status": "active", "age": 10}, false},
+		{map[string]interface{}{"status": "closed", "age": 20}, false},
+	}
+	for i, test := range tests {
+		if got := ExpressionCondition(nil, map[string]interface{}{"expression": source, "document": test.document}); got != test.expected {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, got)
+		}
+	}
+	cached, ok := parsedExpressions.Load(source)
+	if !ok || cached.(parsedExpression).expression.String() != source {
+		t.Fatalf("expected the parsed expression to be cached, got %v", cached)
+	}
+
+	if ExpressionCondition(nil, map[string]interface{}{"expression": `age >`}) {
+		t.Fatal("an invalid expression should not pass")
+	}
+	if cached, ok := parsedExpressions.Load(`age >`); !ok || cached.(parsedExpression).err == nil {
+		t.Fatal("expected the parse error to be cached")
+	}
+}
@@ -21,4 +21,16 @@ func (c *Conditions) RegisterCondition(name string, fn Condition) {
 
 func (c *Conditions) BasicConditions() {
 	c.RegisterCondition("FieldIsProvided", FieldIsProvided)
+	c.RegisterCondition("Expression", ExpressionCondition)
+	c.RegisterCondition("FieldEquals", FieldEquals)
+	c.RegisterCondition("FieldIn", FieldIn)
+	c.RegisterCondition("FieldMatchesRegex", FieldMatchesRegex)
+	c.RegisterCondition("FieldGreaterThan", FieldGreaterThan)
+	c.RegisterCondition("FieldIsEmpty", FieldIsEmpty)
+	c.RegisterCondition("FieldIsMissing", FieldIsMissing)
+
+	// Combinators
+	c.RegisterCondition("AllOf", c.AllOf)
+	c.RegisterCondition("AnyOf", c.AnyOf)
+	c.RegisterCondition("Not", c.Not)
 }
@@ -0,0 +1,999 @@
+package v0
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/conditions"
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"github.com/ashbeelghouri/jsonschematics/operators"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"github.com/ashbeelghouri/jsonschematics/validators"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompiledSchematics is the read-only form of the schematics, all the validators, operators and conditions are resolved
+// and all the target patterns are compiled once, so a single compiled schematics can validate from many goroutines
+type CompiledSchematics struct {
+	schema     Schema
+	fields     []*compiledField
+	separator  string
+	arrayIdKey string
+	locale     string
+	logging    utils.Logger
+	execution  Execution
+}
+
+type compiledField struct {
+	target     string
+	field      Field
+	pattern    *regexp.Regexp
+	collectAll bool
+	// fieldType is the declared type in lower case, it is empty when the type is not checked
+	fieldType string
+	coerce    bool
+	// children are the fields of the object value and items is the field of every element of the array value
+	children   []*compiledField
+	items      *compiledField
+	validators []compiledValidator
+	operators  []compiledOperator
+	conditions []compiledCondition
+	// when is the parsed When expression of the field
+	when *conditions.Expression
+}
+
+type compiledValidator struct {
+	name string
+	// label is the name, numbered when the name is used more than once on the field
+	label    string
+	constant Constant
+	fn       validators.DocumentValidator
+	// conditional validators only run when an action of a condition enables them
+	conditional bool
+}
+
+type compiledOperator struct {
+	name     string
+	constant Constant
+	fn       operators.Op
+	// conditional operators only run when an action of a condition operates them
+	conditional bool
+}
+
+type compiledCondition struct {
+	name       string
+	attributes map[string]interface{}
+	fn         conditions.Condition
+	action     *ConditionalAction
+}
+
+// Compile resolves every name used in the schema against the registered validators, operators and conditions,
+// it returns an error listing everything that could not be resolved
+func (s *Schematics) Compile() (*CompiledSchematics, error) {
+	return s.compile(true)
+}
+
+// compile skips whatever can not be resolved when strict is false, the way the schematics always did at validation time
+func (s *Schematics) compile(strict bool) (*CompiledSchematics, error) {
+	c := CompiledSchematics{
+		separator:  s.Separator,
+		arrayIdKey: s.ArrayIdKey,
+		locale:     s.Locale,
+		logging:    s.Logging,
+		execution:  s.Execution,
+	}
+	if c.separator == "" {
+		c.separator = "."
+	}
+	if c.locale == "" {
+		c.locale = "en"
+	}
+	c.schema = Schema{
+		Version:          s.Schema.Version,
+		DB:               utils.CombineTwoMaps(utils.CombineTwoMaps(nil, s.Schema.DB), s.DB),
+		Fields:           make(map[TargetKey]Field),
+		CollectAllErrors: s.Schema.CollectAllErrors,
+		Strict:           s.Schema.Strict,
+		AdditionalFields: s.Schema.AdditionalFields,
+	}
+
+	order, unresolved := dependencyOrder(s.Schema.Fields)
+	for _, target := range order {
+		cf, missing := s.compileField(string(target), s.Schema.Fields[target])
+		unresolved = append(unresolved, missing...)
+		c.fields = append(c.fields, cf)
+		c.schema.Fields[target] = cf.field
+	}
+
+	if len(unresolved) > 0 {
+		if strict {
+			return nil, fmt.Errorf("unable to compile the schematics: %s", strings.Join(unresolved, ", "))
+		}
+		s.Logging.ERROR("skipping the unresolved names in the schema", unresolved)
+	}
+	return &c, nil
+}
+
+func (s *Schematics) compileField(target string, field Field) (*compiledField, []string) {
+	var unresolved []string
+	cf := compiledField{
+		target: target,
+		field:  field.clone(),
+	}
+	cf.field.Target = target
+	cf.field.logging = s.Logging
+	cf.collectAll = cf.field.collectAllErrors(s.Schema)
+
+	pattern, err := regexp.Compile(utils.ConvertKeyToRegex(target))
+	if err != nil {
+		unresolved = append(unresolved, fmt.Sprintf("target %s is not a valid pattern", target))
+		pattern = regexp.MustCompile("^" + regexp.QuoteMeta(target) + "$")
+	}
+	cf.pattern = pattern
+
+	cf.fieldType = strings.ToLower(strings.TrimSpace(cf.field.Type))
+	if cf.fieldType != "" && !knownFieldType(cf.fieldType) {
+		unresolved = append(unresolved, fmt.Sprintf("type %s on %s", cf.field.Type, target))
+		cf.fieldType = ""
+	}
+	cf.coerce = cf.field.Coerce
+
+	validatorComponents := cf.field.ValidatorComponents()
+	for i, component := range validatorComponents {
+		if component.Name == "" || utils.StringInStrings(strings.ToUpper(component.Name), utils.ExcludedValidators) {
+			continue
+		}
+		fn, ok := s.Validators.GetDocument(component.Name)
+		if !ok {
+			unresolved = append(unresolved, fmt.Sprintf("validator %s on %s", component.Name, target))
+			continue
+		}
+		cf.validators = append(cf.validators, compiledValidator{
+			name:     component.Name,
+			label:    componentLabel(validatorComponents, i),
+			constant: component.Constant,
+			fn:       fn,
+		})
+	}
+
+	for _, component := range cf.field.OperatorComponents() {
+		fn, ok := s.Operators.OpFunctions[component.Name]
+		if !ok {
+			unresolved = append(unresolved, fmt.Sprintf("operator %s on %s", component.Name, target))
+			continue
+		}
+		cf.operators = append(cf.operators, compiledOperator{name: component.Name, constant: component.Constant, fn: fn})
+	}
+
+	conditionNames := make([]string, 0, len(cf.field.Conditions))
+	for name := range cf.field.Conditions {
+		conditionNames = append(conditionNames, name)
+	}
+	sort.Strings(conditionNames)
+	for _, name := range conditionNames {
+		fn, ok := s.Conditions.ConditionFns[name]
+		if missing := s.Conditions.Unregistered(name, cf.field.Conditions[name].Attributes); len(missing) > 0 {
+			for _, m := range missing {
+				unresolved = append(unresolved, fmt.Sprintf("condition %s on %s", m, target))
+			}
+			if !ok {
+				continue
+			}
+		}
+		cf.conditions = append(cf.conditions, compiledCondition{name: name, attributes: cf.field.Conditions[name].Attributes, fn: fn, action: cf.field.Conditions[name].Action})
+	}
+	unresolved = append(unresolved, s.compileActions(&cf)...)
+
+	if strings.TrimSpace(cf.field.When) != "" {
+		expression, err := conditions.ParseExpression(cf.field.When)
+		if err != nil {
+			unresolved = append(unresolved, fmt.Sprintf("when on %s: %v", target, err))
+		}
+		cf.when = expression
+	}
+
+	childOrder, missingOrder := dependencyOrder(cf.field.Fields)
+	for _, m := range missingOrder {
+		unresolved = append(unresolved, fmt.Sprintf("%s in %s", m, target))
+	}
+	for _, childTarget := range childOrder {
+		child, missing := s.compileField(string(childTarget), cf.field.Fields[childTarget])
+		for _, m := range missing {
+			unresolved = append(unresolved, fmt.Sprintf("%s in %s", m, target))
+		}
+		cf.children = append(cf.children, child)
+	}
+	if cf.field.Items != nil {
+		items, missing := s.compileField("*", *cf.field.Items)
+		for _, m := range missing {
+			unresolved = append(unresolved, fmt.Sprintf("%s in %s", m, target))
+		}
+		cf.items = items
+	}
+
+	return &cf, unresolved
+}
+
+// dependencyOrder is the DependencyOrder of the fields, the sorted targets are used when the dependencies are circular
+func dependencyOrder(fields map[TargetKey]Field) ([]TargetKey, []string) {
+	order, err := DependencyOrder(fields)
+	if err != nil {
+		return sortedTargets(fields), []string{err.Error()}
+	}
+	return order, nil
+}
+
+// componentLabel numbers the components that have the same name, e.g. MatchRegex#1 and MatchRegex#2
+func componentLabel(components []Component, index int) string {
+	name := components[index].Name
+	count, position := 0, 0
+	for i, component := range components {
+		if component.Name == name {
+			count++
+			if i <= index {
+				position = count
+			}
+		}
+	}
+	if count < 2 {
+		return name
+	}
+	return fmt.Sprintf("%s#%d", name, position)
+}
+
+func sortedTargets(fields map[TargetKey]Field) []TargetKey {
+	targets := make([]TargetKey, 0, len(fields))
+	for target := range fields {
+		targets = append(targets, target)
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+	return targets
+}
+
+func sortedNames(constants map[string]Constant) []string {
+	names := make([]string, 0, len(constants))
+	for name := range constants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// orderedNames returns the names in the declared order, names that are not declared in the order are sorted at the end
+func orderedNames(constants map[string]Constant, order []string) []string {
+	names := make([]string, 0, len(constants))
+	seen := make(map[string]bool, len(constants))
+	for _, name := range order {
+		if _, exists := constants[name]; exists && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedNames(constants) {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clone copies the maps and slices of the field, so the compiled field is not changed with the schematics
+func (f Field) clone() Field {
+	f.DependsOn = append([]string(nil), f.DependsOn...)
+	f.Tags = append([]string(nil), f.Tags...)
+	f.ValidatorsOrder = append([]string(nil), f.ValidatorsOrder...)
+	f.OperatorsOrder = append([]string(nil), f.OperatorsOrder...)
+	f.FieldsOrder = append([]TargetKey(nil), f.FieldsOrder...)
+	f.ValidatorPipeline = cloneComponents(f.ValidatorPipeline)
+	f.OperatorPipeline = cloneComponents(f.OperatorPipeline)
+	if f.CollectAllErrors != nil {
+		collectAll := *f.CollectAllErrors
+		f.CollectAllErrors = &collectAll
+	}
+	if f.Fields != nil {
+		fieldsCopy := make(map[TargetKey]Field, len(f.Fields))
+		for target, child := range f.Fields {
+			fieldsCopy[target] = child.clone()
+		}
+		f.Fields = fieldsCopy
+	}
+	if f.Items != nil {
+		items := f.Items.clone()
+		f.Items = &items
+	}
+	f.Validators = cloneConstants(f.Validators)
+	f.Operators = cloneConstants(f.Operators)
+	if f.Conditions != nil {
+		conditionsCopy := make(map[string]Condition, len(f.Conditions))
+		for name, condition := range f.Conditions {
+			condition.Attributes = utils.CombineTwoMaps(nil, condition.Attributes)
+			conditionsCopy[name] = condition
+		}
+		f.Conditions = conditionsCopy
+	}
+	f.Value = nil
+	f.Provided = false
+	f.Status = ""
+	f.Errors = errorHandler.Errors{}
+	return f
+}
+
+func cloneConstants(constants map[string]Constant) map[string]Constant {
+	if constants == nil {
+		return nil
+	}
+	constantsCopy := make(map[string]Constant, len(constants))
+	for name, constant := range constants {
+		constant.Attributes = utils.CombineTwoMaps(nil, constant.Attributes)
+		constantsCopy[name] = constant
+	}
+	return constantsCopy
+}
+
+func (c *CompiledSchematics) flatten(data map[string]interface{}) map[string]interface{} {
+	var dMap utils.DataMap
+	dMap.FlattenTheMap(data, "", c.separator)
+	if dMap.Data == nil {
+		return map[string]interface{}{}
+	}
+	return dMap.Data
+}
+
+func (cf *compiledField) match(flatData map[string]interface{}, separator string) map[string]interface{} {
+	return utils.FindMatchingKeysByRegex(flatData, cf.pattern, cf.target, separator)
+}
+
+// assign matches the flat data with every field, the schematics fields are not changed
+func (c *CompiledSchematics) assign(compiled []*compiledField, flatData map[string]interface{}) map[TargetKey]Field {
+	fields := make(map[TargetKey]Field, len(compiled))
+	for _, cf := range compiled {
+		f := cf.field
+		f.Value = cf.match(flatData, c.separator)
+		f.Provided = len(f.Value) > 0
+		fields[TargetKey(cf.target)] = f
+	}
+	return fields
+}
+
+func (c *CompiledSchematics) getDB(fields map[TargetKey]Field) map[string]interface{} {
+	db := utils.CombineTwoMaps(nil, c.schema.DB)
+	for _, cf := range c.fields {
+		field := fields[TargetKey(cf.target)]
+		if !field.AddToDB {
+			continue
+		}
+		if len(field.Value) == 1 {
+			if value := utils.GetFirstFromMap(field.Value); value != nil {
+				db[cf.target] = value
+			}
+		} else if len(field.Value) > 1 {
+			var values []interface{}
+			for _, key := range sortedKeys(field.Value) {
+				values = append(values, field.Value[key])
+			}
+			db[cf.target] = values
+		}
+	}
+	return db
+}
+
+func (c *CompiledSchematics) Validate(jsonData interface{}) *errorHandler.Errors {
+	return c.ValidateContext(context.Background(), jsonData)
+}
+
+func (c *CompiledSchematics) ValidateContext(ctx context.Context, jsonData interface{}) *errorHandler.Errors {
+	var baseError errorHandler.Error
+	var errs errorHandler.Errors
+	baseError.Validator = "validate-object"
+	if err := ctx.Err(); err != nil {
+		return contextErrors(err)
+	}
+
+	dataBytes, err := json.Marshal(jsonData)
+	if err != nil {
+		baseError.AddMessage("en", "data is not valid json")
+		errs.AddError("whole-data", baseError)
+		return &errs
+	}
+
+	var obj map[string]interface{}
+	var arr []map[string]interface{}
+	if err := json.Unmarshal(dataBytes, &obj); err == nil {
+		return c.ValidateObjectContext(ctx, &obj, nil)
+	} else if err := json.Unmarshal(dataBytes, &arr); err == nil {
+		return c.ValidateArrayContext(ctx, arr)
+	} else {
+		baseError.AddMessage("en", "invalid format provided for the data, can only be map[string]interface or []map[string]interface")
+		errs.AddError("whole-data", baseError)
+		return &errs
+	}
+}
+
+func (c *CompiledSchematics) ValidateObject(jsonData *map[string]interface{}, id *string) *errorHandler.Errors {
+	return c.ValidateObjectContext(context.Background(), jsonData, id)
+}
+
+func (c *CompiledSchematics) ValidateObjectContext(ctx context.Context, jsonData *map[string]interface{}, id *string) *errorHandler.Errors {
+	return c.validateObject(ctx, jsonData, id, c.execution.workers())
+}
+
+// validateObject validates the values of every field with the number of workers
+func (c *CompiledSchematics) validateObject(ctx context.Context, jsonData *map[string]interface{}, id *string, workers int) *errorHandler.Errors {
+	flatData := c.flatten(*jsonData)
+	fields := c.assign(c.fields, flatData)
+	db := c.getDB(fields)
+
+	v := validation{id: id, db: db, document: flatData}
+	errorMessages := c.validateFields(ctx, c.fields, fields, flatData, "", &v, workers)
+	if errorMessages.HasErrors() {
+		return errorMessages
+	}
+	return nil
+}
+
+// join prefixes the key with the key of the object it is nested in
+func (c *CompiledSchematics) join(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + c.separator + key
+}
+
+// validateFields validates the fields assigned from the flat data of an object, prefix is the key of the object when it is nested
+func (c *CompiledSchematics) validateFields(ctx context.Context, compiled []*compiledField, fields map[TargetKey]Field, flatData map[string]interface{}, prefix string, v *validation, workers int) *errorHandler.Errors {
+	var errorMessages errorHandler.Errors
+	schema := c.schema
+	schema.Fields = fields
+
+	// failed are the targets of the fields with errors, the compiled fields are in the order of their dependencies
+	failed := make(map[string]bool)
+	for _, cf := range compiled {
+		if err := ctx.Err(); err != nil {
+			errorMessages.MergeErrors(contextErrors(err))
+			return &errorMessages
+		}
+		field := fields[TargetKey(cf.target)]
+		state := cf.evaluateConditions(field, schema, v.document, c.separator)
+		if state.skip {
+			continue
+		}
+
+		if state.required && !field.Provided {
+			target := c.join(prefix, cf.target)
+			errorMessages.AddError(target, *requiredError(target, v.id))
+			failed[cf.target] = true
+			continue
+		}
+
+		if field.Provided && len(field.DependsOn) > 0 {
+			target := c.join(prefix, cf.target)
+			if err := c.dependencyError(field, fields, flatData, failed, target, v.id); err != nil {
+				c.logging.DEBUG("dependencies of", cf.target, "are not met")
+				errorMessages.AddError(target, *err)
+				failed[cf.target] = true
+				continue
+			}
+		}
+
+		values := make(map[string]interface{}, len(field.Value))
+		for key, value := range field.Value {
+			values[c.join(prefix, key)] = value
+		}
+		fieldErrors := c.validateField(ctx, cf, values, v, state, workers)
+		failed[cf.target] = fieldErrors.HasErrors()
+		errorMessages.MergeErrors(fieldErrors)
+		if err := ctx.Err(); err != nil {
+			errorMessages.MergeErrors(contextErrors(err))
+			return &errorMessages
+		}
+	}
+
+	if !c.schema.allowsAdditionalFields() {
+		errorMessages.MergeErrors(c.unknownFields(compiled, flatData, prefix, v.id))
+	}
+	return &errorMessages
+}
+
+// unknownFields reports the keys of the flat data that are not covered by any target, the ArrayIdKey is always accepted
+func (c *CompiledSchematics) unknownFields(compiled []*compiledField, flatData map[string]interface{}, prefix string, id *string) *errorHandler.Errors {
+	var errs errorHandler.Errors
+	for _, key := range sortedKeys(flatData) {
+		if (prefix == "" && key == c.arrayIdKey) || c.covers(compiled, key, flatData[key]) {
+			continue
+		}
+		target := c.join(prefix, key)
+		errs.AddError(target, *newValidationError(target, flatData[key], id, "unknown-field", Constant{}, errors.New("unknown field")))
+	}
+	return &errs
+}
+
+// covers tells if the flat key is matched by a target, or is an empty object or array that a target goes inside of
+func (c *CompiledSchematics) covers(compiled []*compiledField, key string, value interface{}) bool {
+	for _, cf := range compiled {
+		if len(utils.FindMatchingKeysByRegex(map[string]interface{}{key: value}, cf.pattern, cf.target, c.separator)) > 0 {
+			return true
+		}
+		if isEmptyValue(value) && targetHasPrefix(cf.target, key, c.separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetHasPrefix tells if the target is nested inside the key, * in the target matches any part of the key
+func targetHasPrefix(target string, key string, separator string) bool {
+	targetParts := strings.Split(target, separator)
+	keyParts := strings.Split(key, separator)
+	if len(keyParts) >= len(targetParts) {
+		return false
+	}
+	for i, part := range keyParts {
+		if targetParts[i] != "*" && targetParts[i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateConditions tells if the field should be validated and takes the actions of the conditions,
+// the When expression and the conditions are evaluated over the document
+func (cf *compiledField) evaluateConditions(field Field, schema Schema, document map[string]interface{}, separator string) fieldState {
+	state := fieldState{required: field.IsRequired}
+	if cf.when != nil && !cf.when.Evaluate(document, separator) {
+		cf.field.logging.DEBUG("skipping the field, when is false:", cf.when.String())
+		state.skip = true
+		return state
+	}
+	for _, condition := range cf.conditions {
+		cf.field.logging.DEBUG("performing conditions", condition.name)
+		attrs := utils.CombineTwoMaps(nil, condition.attributes)
+		attrs["schema"] = schema
+		attrs["document"] = document
+		attrs["separator"] = separator
+		fieldMap := field.AsMap()
+		if fieldMap == nil {
+			state.skip = true
+			return state
+		}
+		passed := condition.fn(*fieldMap, attrs)
+		if condition.action == nil {
+			if !passed {
+				state.skip = true
+				return state
+			}
+			continue
+		}
+		actions := condition.action.Error
+		if passed {
+			actions = condition.action.Success
+		}
+		for _, action := range actions {
+			state.apply(action)
+		}
+	}
+	return state
+}
+
+// validateField runs the validators on every value of the field, the errors are in the order of the keys of the values.
+// When all the errors are collected the target of every error is the key of the value with the validator, e.g. user.name[IsString]
+func (c *CompiledSchematics) validateField(ctx context.Context, cf *compiledField, values map[string]interface{}, v *validation, state fieldState, workers int) *errorHandler.Errors {
+	var errs errorHandler.Errors
+	if len(values) == 0 {
+		return nil
+	}
+	keys := sortedKeys(values)
+	results := make([][]valueError, len(keys))
+	forEach(ctx, len(keys), workers, func(i int) {
+		results[i] = c.validateValue(ctx, cf, keys[i], values[keys[i]], v, state)
+	})
+	for _, valueErrors := range results {
+		for _, vErr := range valueErrors {
+			errs.AddError(vErr.target, *vErr.err)
+		}
+	}
+	return &errs
+}
+
+// validation is the state of a single validation call, it is shared by all the fields
+type validation struct {
+	id *string
+	db map[string]interface{}
+	// document is the flat data, the document validators can look up the other values in it
+	document map[string]interface{}
+}
+
+type valueError struct {
+	target string
+	err    *errorHandler.Error
+}
+
+// forEach calls fn with every index from 0 to n on the number of workers, no new index is started after ctx is done.
+// It returns once all the started calls are finished
+func forEach(ctx context.Context, n int, workers int, fn func(int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			fn(i)
+		}
+		return
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			break feed
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// validateValue returns the error of the first failing validator, or the errors of all the failing validators when they are collected
+func (c *CompiledSchematics) validateValue(ctx context.Context, cf *compiledField, key string, value interface{}, v *validation, state fieldState) []valueError {
+	var errs []valueError
+	if value == nil {
+		// null is only an error for the fields that check their value, a field with only operators or a description accepts it
+		if cf.field.Nullable || (len(cf.validators) == 0 && cf.fieldType == "") {
+			return nil
+		}
+		return []valueError{{target: key, err: nullError(key, v.id)}}
+	}
+	if cf.field.AllowEmpty && isEmptyValue(value) {
+		return nil
+	}
+	if cf.coerce {
+		value = coerceValue(cf.fieldType, value)
+	}
+	if err := checkType(cf.fieldType, value); err != nil {
+		return []valueError{{target: key, err: newValidationError(key, value, v.id, "is-type", Constant{}, err)}}
+	}
+	for _, validator := range cf.validators {
+		// Stop validating the value as soon as the caller is not waiting for the result anymore
+		if ctx.Err() != nil {
+			return errs
+		}
+		if !state.runsValidator(validator) {
+			continue
+		}
+		attrs := utils.CombineTwoMaps(nil, validator.constant.Attributes)
+		attrs["DB"] = v.db
+		if err := validator.fn(ctx, value, attrs, validators.Document{Data: v.document, Key: key, Separator: c.separator}); err != nil {
+			vErr := valueError{target: key, err: newValidationError(key, value, v.id, validator.name, validator.constant, err)}
+			if !cf.collectAll {
+				errs = append(errs, vErr)
+				break
+			}
+			vErr.target = fmt.Sprintf("%s[%s]", key, validator.label)
+			errs = append(errs, vErr)
+		}
+	}
+	return append(errs, c.validateNested(ctx, cf, key, value, v)...)
+}
+
+// validateNested validates the object value with the fields of the field and every element of the array value with its items
+func (c *CompiledSchematics) validateNested(ctx context.Context, cf *compiledField, key string, value interface{}, v *validation) []valueError {
+	var errs []valueError
+	if obj, ok := value.(map[string]interface{}); ok && len(cf.children) > 0 {
+		flatData := c.flatten(obj)
+		nestedErrors := c.validateFields(ctx, cf.children, c.assign(cf.children, flatData), flatData, key, v, 1)
+		for _, target := range nestedErrors.Targets() {
+			nestedError := nestedErrors.Messages[target]
+			errs = append(errs, valueError{target: string(target), err: &nestedError})
+		}
+	}
+	if cf.items != nil && value != nil {
+		if items := reflect.ValueOf(value); items.Kind() == reflect.Slice || items.Kind() == reflect.Array {
+			for i := 0; i < items.Len(); i++ {
+				if ctx.Err() != nil {
+					return errs
+				}
+				errs = append(errs, c.validateValue(ctx, cf.items, c.join(key, strconv.Itoa(i)), items.Index(i).Interface(), v, fieldState{})...)
+			}
+		}
+	}
+	return errs
+}
+
+// isEmptyValue tells if the value is an empty string, array or object
+func isEmptyValue(value interface{}) bool {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+func requiredError(target string, id *string) *errorHandler.Error {
+	var errorMessage errorHandler.Error
+	errorMessage.DataTarget = target
+	errorMessage.Validator = "is-required"
+	if id != nil {
+		errorMessage.ID = *id
+	}
+	errorMessage.AddMessage("en", "please provide the value for this required field")
+	return &errorMessage
+}
+
+func nullError(key string, id *string) *errorHandler.Error {
+	var errorMessage errorHandler.Error
+	errorMessage.DataTarget = key
+	errorMessage.Validator = "is-nullable"
+	if id != nil {
+		errorMessage.ID = *id
+	}
+	errorMessage.AddMessage("en", "null is not allowed for this field")
+	return &errorMessage
+}
+
+func newValidationError(key string, value interface{}, id *string, name string, constant Constant, err error) *errorHandler.Error {
+	var errorMessage errorHandler.Error
+	errorMessage.DataTarget = key
+	errorMessage.Value = value
+	errorMessage.Validator = name
+	if id != nil {
+		errorMessage.ID = *id
+	}
+	// the custom error message is preferred over the one returned by the validator
+	if constant.Error != "" {
+		errorMessage.AddMessage("en", constant.Error)
+	} else {
+		errorMessage.AddMessage("en", err.Error())
+	}
+	for locale, msg := range constant.L10n.Error {
+		if m, ok := msg.(string); ok {
+			errorMessage.AddMessage(locale, m)
+		}
+	}
+	for locale, nameValue := range constant.L10n.Name {
+		if n, ok := nameValue.(string); ok {
+			errorMessage.AddL10n(name, locale, n)
+		}
+	}
+	return &errorMessage
+}
+
+func (c *CompiledSchematics) ValidateArray(jsonData []map[string]interface{}) *errorHandler.Errors {
+	return c.ValidateArrayContext(context.Background(), jsonData)
+}
+
+// ValidateArrayContext validates the rows with the execution strategy of the schematics, the errors are in the order of the rows
+func (c *CompiledSchematics) ValidateArrayContext(ctx context.Context, jsonData []map[string]interface{}) *errorHandler.Errors {
+	c.logging.DEBUG("validating the array")
+	var errs errorHandler.Errors
+	results := make([]*errorHandler.Errors, len(jsonData))
+	forEach(ctx, len(jsonData), c.execution.workers(), func(i int) {
+		id := c.rowID(jsonData[i], fmt.Sprintf("row-%d", i))
+		// the rows are already spread over the workers, so the values of a row are validated one by one
+		results[i] = c.validateObject(ctx, &jsonData[i], &id, 1)
+	})
+
+	for _, errorMessages := range results {
+		if errorMessages.HasErrors() {
+			c.logging.ERROR("has errors", errorMessages.GetStrings("en", "%data\n"))
+			errs.MergeErrors(errorMessages)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		errs.MergeErrors(contextErrors(err))
+	}
+
+	if errs.HasErrors() {
+		return &errs
+	}
+	return nil
+}
+
+// rowID is the value of the ArrayIdKey in the row, or the fallback when the row has no id
+func (c *CompiledSchematics) rowID(row map[string]interface{}, fallback string) string {
+	if c.arrayIdKey != "" {
+		if arrayId, exists := c.flatten(row)[c.arrayIdKey]; exists && arrayId != nil {
+			return fmt.Sprint(arrayId)
+		}
+	}
+	return fallback
+}
+
+// operate performs the operators of the field on the value, then the operators of its fields and items on the nested values.
+// The conditions are only evaluated over the document when an action of a condition operates one of the operators
+func (c *CompiledSchematics) operate(ctx context.Context, cf *compiledField, value interface{}, document map[string]interface{}) interface{} {
+	if cf.coerce {
+		value = coerceValue(cf.fieldType, value)
+	}
+	var state fieldState
+	for _, operator := range cf.operators {
+		if operator.conditional {
+			state = cf.evaluateConditions(cf.field, c.schema, document, c.separator)
+			break
+		}
+	}
+	for _, operator := range cf.operators {
+		if !state.runsOperator(operator) {
+			continue
+		}
+		result := operator.fn(value, utils.CombineTwoMaps(nil, operator.constant.Attributes))
+		if result != nil {
+			value = *result
+		}
+	}
+	if obj, ok := value.(map[string]interface{}); ok && len(cf.children) > 0 {
+		if operated := c.operateFields(ctx, cf.children, obj, document); operated != nil {
+			value = operated
+		}
+	}
+	if items, ok := value.([]interface{}); ok && cf.items != nil {
+		operated := make([]interface{}, len(items))
+		for i, item := range items {
+			operated[i] = c.operate(ctx, cf.items, item, document)
+		}
+		value = operated
+	}
+	return value
+}
+
+func (c *CompiledSchematics) Operate(data interface{}) (interface{}, *errorHandler.Errors) {
+	return c.OperateContext(context.Background(), data)
+}
+
+func (c *CompiledSchematics) OperateContext(ctx context.Context, data interface{}) (interface{}, *errorHandler.Errors) {
+	var errorMessages errorHandler.Errors
+	var baseError errorHandler.Error
+	baseError.Validator = "operate-on-schema"
+	if err := ctx.Err(); err != nil {
+		return nil, contextErrors(err)
+	}
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		c.logging.ERROR("[operate] error converting the data into bytes", err)
+		baseError.AddMessage("en", "data is not valid json")
+		errorMessages.AddError("whole-data", baseError)
+		return nil, &errorMessages
+	}
+
+	dataType, item := utils.IsValidJson(bytes)
+	if item == nil {
+		c.logging.ERROR("[operate] error occurred when checking if this data is an array or object")
+		baseError.AddMessage("en", "can not convert the data into json")
+		errorMessages.AddError("whole-data", baseError)
+		return nil, &errorMessages
+	}
+
+	if dataType == "object" {
+		obj := item.(map[string]interface{})
+		results := c.OperateOnObjectContext(ctx, obj)
+		if err := ctx.Err(); err != nil {
+			return nil, contextErrors(err)
+		}
+		if results != nil {
+			return results, nil
+		} else {
+			baseError.AddMessage("en", "operation on object unsuccessful")
+			errorMessages.AddError("whole-data", baseError)
+			return nil, &errorMessages
+		}
+	} else if dataType == "array" {
+		arr := item.([]map[string]interface{})
+		results := c.OperateOnArrayContext(ctx, arr)
+		if err := ctx.Err(); err != nil {
+			return nil, contextErrors(err)
+		}
+		if results != nil && len(*results) > 0 {
+			return results, nil
+		} else {
+			baseError.AddMessage("en", "operation on array unsuccessful")
+			errorMessages.AddError("whole-data", baseError)
+			return nil, &errorMessages
+		}
+	}
+
+	return data, nil
+}
+
+func (c *CompiledSchematics) OperateOnObject(data map[string]interface{}) *map[string]interface{} {
+	return c.OperateOnObjectContext(context.Background(), data)
+}
+
+// OperateOnObjectContext returns nil when ctx is done before all the fields are operated
+func (c *CompiledSchematics) OperateOnObjectContext(ctx context.Context, data map[string]interface{}) *map[string]interface{} {
+	d := c.operateFields(ctx, c.fields, data, nil)
+	if d == nil {
+		return nil
+	}
+	return &d
+}
+
+// operateFields performs the operations of the fields on the object, it returns nil when ctx is done.
+// document is the flat data of the top level object, it is nil for the top level object itself
+func (c *CompiledSchematics) operateFields(ctx context.Context, compiled []*compiledField, data map[string]interface{}, document map[string]interface{}) map[string]interface{} {
+	data = c.flatten(data)
+	if document == nil {
+		document = utils.CombineTwoMaps(nil, data)
+	}
+	for _, cf := range compiled {
+		if ctx.Err() != nil {
+			return nil
+		}
+		matchingKeys := cf.match(data, c.separator)
+		if len(matchingKeys) == 0 && cf.field.Default != nil && !strings.Contains(cf.target, "*") {
+			// the default is flattened under the target, so the fields nested in it are operated as well
+			defaults := c.flatten(map[string]interface{}{cf.target: cf.field.Default})
+			for key, value := range defaults {
+				data[key] = value
+			}
+			matchingKeys = cf.match(data, c.separator)
+		}
+		for key, value := range matchingKeys {
+			if _, isFlat := data[key]; isFlat {
+				data[key] = c.operate(ctx, cf, value, document)
+				continue
+			}
+			// the value is an object or array built from the nested keys, it is flattened back after the operations
+			for flatKey := range data {
+				if strings.HasPrefix(flatKey, key+c.separator) {
+					delete(data, flatKey)
+				}
+			}
+			for flatKey, flatValue := range c.flatten(map[string]interface{}{key: c.operate(ctx, cf, value, document)}) {
+				data[flatKey] = flatValue
+			}
+		}
+	}
+	return utils.DeflateMap(data, c.separator)
+}
+
+func (c *CompiledSchematics) OperateOnArray(data []map[string]interface{}) *[]map[string]interface{} {
+	return c.OperateOnArrayContext(context.Background(), data)
+}
+
+// OperateOnArrayContext returns nil when ctx is done before all the rows are operated
+func (c *CompiledSchematics) OperateOnArrayContext(ctx context.Context, data []map[string]interface{}) *[]map[string]interface{} {
+	results := make([]*map[string]interface{}, len(data))
+	forEach(ctx, len(data), c.execution.workers(), func(i int) {
+		results[i] = c.OperateOnObjectContext(ctx, data[i])
+	})
+	var obj []map[string]interface{}
+	for _, result := range results {
+		if result == nil {
+			return nil
+		}
+		obj = append(obj, *result)
+	}
+	if len(obj) > 0 {
+		return &obj
+	}
+	return nil
+}
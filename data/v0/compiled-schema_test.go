@@ -0,0 +1,455 @@
+package v0
+
+import (
+	"context"
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// validationCase is the data to validate and the targets of the errors it should have, in their order
+type validationCase struct {
+	name     string
+	data     map[string]interface{}
+	expected []string
+}
+
+func loadSchema(t *testing.T, schema map[string]interface{}) *Schematics {
+	t.Helper()
+	var schematics Schematics
+	if err := schematics.LoadMap(schema); err != nil {
+		t.Fatal(err)
+	}
+	return &schematics
+}
+
+func runValidationCases(t *testing.T, schematics *Schematics, cases []validationCase) {
+	t.Helper()
+	for _, test := range cases {
+		errs := schematics.Validate(test.data)
+		if got := errorTargets(errs); strings.Join(got, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: expected errors on %v, got %v", test.name, test.expected, errs.GetStrings("en", "%target: %message"))
+		}
+	}
+}
+
+func errorTargets(errs *errorHandler.Errors) []string {
+	var targets []string
+	for _, target := range errs.Targets() {
+		targets = append(targets, string(target))
+	}
+	return targets
+}
+
+// errorStrings returns the errors as target: message in the order of the targets
+func errorStrings(errs *errorHandler.Errors) []string {
+	var messages []string
+	for _, target := range errs.Targets() {
+		messages = append(messages, string(target)+": "+errs.Messages[target].Message["en"])
+	}
+	return messages
+}
+
+func hasErrorOn(errs *errorHandler.Errors, target string) bool {
+	if errs == nil {
+		return false
+	}
+	_, exists := errs.Messages[errorHandler.Target(target)]
+	return exists
+}
+
+func TestValidateContextDeadline(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"user.name": map[string]interface{}{
+				"validators": map[string]interface{}{"SlowValidator": map[string]interface{}{}},
+			},
+		},
+	})
+	schematics.Validators.RegisterValidatorContext("SlowValidator", func(ctx context.Context, _ interface{}, _ map[string]interface{}) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	errs := schematics.ValidateContext(ctx, map[string]interface{}{"user": map[string]interface{}{"name": "john"}})
+	if time.Since(started) > time.Second {
+		t.Fatal("validation did not stop at the deadline")
+	}
+	if !hasErrorOn(errs, "whole-data") {
+		t.Fatalf("expected whole-data context error, got %v", errs.Messages)
+	}
+}
+
+func TestCompiledSchematicsConcurrentValidation(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"user.profile.age": map[string]interface{}{
+				"validators": map[string]interface{}{"MaxAllowed": map[string]interface{}{"attributes": map[string]interface{}{"max": 20}}},
+			},
+		},
+	})
+	compiled, err := schematics.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(age int) {
+			defer wg.Done()
+			errs := compiled.Validate(map[string]interface{}{"user": map[string]interface{}{"profile": map[string]interface{}{"age": age}}})
+			if errs.HasErrors() != (age > 20) {
+				t.Errorf("age %d: unexpected errors %v", age, errs.Messages)
+			}
+		}(i * 2)
+	}
+	wg.Wait()
+
+	schematics.Schema.Fields["user.profile.age"].Validators["UnknownValidator"] = Constant{}
+	if _, err := schematics.Compile(); err == nil {
+		t.Fatal("expected unknown validators to fail the compilation")
+	}
+}
+
+func TestCollectAllErrorsInDeclaredOrder(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"name": map[string]interface{}{
+				"validators": []interface{}{
+					map[string]interface{}{"name": "MinLengthAllowed", "attributes": map[string]interface{}{"min": 10}},
+					map[string]interface{}{"name": "MaxLengthAllowed", "attributes": map[string]interface{}{"max": 2}},
+					map[string]interface{}{"name": "IsEmail"},
+				},
+			},
+		},
+	})
+	collectAll, stopAtFirst := true, false
+	tests := []struct {
+		name       string
+		schema     bool
+		field      *bool
+		validators string
+	}{
+		{"first failure", false, nil, "MinLengthAllowed"},
+		{"all the failures", true, nil, "MinLengthAllowed,MaxLengthAllowed,IsEmail"},
+		{"field overrides the schema", true, &stopAtFirst, "MinLengthAllowed"},
+		{"field collects all", false, &collectAll, "MinLengthAllowed,MaxLengthAllowed,IsEmail"},
+	}
+	for _, test := range tests {
+		schematics.Schema.CollectAllErrors = test.schema
+		field := schematics.Schema.Fields["name"]
+		field.CollectAllErrors = test.field
+		schematics.Schema.Fields["name"] = field
+
+		errs := schematics.Validate(map[string]interface{}{"name": "abc"})
+		var validators []string
+		for _, target := range errs.Targets() {
+			validators = append(validators, errs.Messages[target].Validator)
+		}
+		if strings.Join(validators, ",") != test.validators {
+			t.Errorf("%s: expected the failures %s in the declared order, got %v", test.name, test.validators, validators)
+		}
+	}
+}
+
+func TestNullableEmptyDefaultAndRequired(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"nickname": map[string]interface{}{
+				"nullable":   true,
+				"validators": map[string]interface{}{"IsString": map[string]interface{}{}},
+			},
+			"tags": map[string]interface{}{
+				"allow_empty": true,
+				"validators":  map[string]interface{}{"IsString": map[string]interface{}{}},
+			},
+			"email": map[string]interface{}{
+				"required":   true,
+				"validators": map[string]interface{}{"IsEmail": map[string]interface{}{}},
+			},
+			"role": map[string]interface{}{
+				"default":   "  USER ",
+				"operators": []interface{}{map[string]interface{}{"name": "Trim"}, map[string]interface{}{"name": "LowerCase"}},
+			},
+			"note":  map[string]interface{}{"description": "free text"},
+			"count": map[string]interface{}{"type": "integer"},
+		},
+	})
+	runValidationCases(t, schematics, []validationCase{
+		{"null nullable and empty allow_empty", map[string]interface{}{"nickname": nil, "tags": []interface{}{}, "email": "a@b.co"}, nil},
+		{"missing required and null not nullable", map[string]interface{}{"tags": nil}, []string{"email", "tags"}},
+		{"null without validators or type", map[string]interface{}{"email": "a@b.co", "role": nil, "note": nil}, nil},
+		{"null with a type", map[string]interface{}{"email": "a@b.co", "count": nil}, []string{"count"}},
+	})
+	if errs := schematics.Validate(map[string]interface{}{}); errs.Messages["email"].Validator != "is-required" {
+		t.Fatalf("expected the is-required error, got %v", errs.Messages["email"])
+	}
+
+	result, errs := schematics.Operate(map[string]interface{}{"email": "a@b.co"})
+	if errs.HasErrors() {
+		t.Fatal(errs.Messages)
+	}
+	if (*result.(*map[string]interface{}))["role"] != "user" {
+		t.Fatalf("the default should be set and operated, got %v", result)
+	}
+}
+
+func TestFieldTypesAndCoercion(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"age":      map[string]interface{}{"type": "integer", "coerce": true, "validators": map[string]interface{}{"MinAllowed": map[string]interface{}{"attributes": map[string]interface{}{"min": 18}}}},
+			"active":   map[string]interface{}{"type": "boolean", "coerce": true},
+			"name":     map[string]interface{}{"type": "string"},
+			"birthday": map[string]interface{}{"type": "date"},
+			"address":  map[string]interface{}{"type": "object"},
+			"tags":     map[string]interface{}{"type": "array", "validators": map[string]interface{}{"ArrayLengthMax": map[string]interface{}{"attributes": map[string]interface{}{"max": 2}}}},
+		},
+	})
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	runValidationCases(t, schematics, []validationCase{
+		{"valid", map[string]interface{}{
+			"age":      "42",
+			"active":   "true",
+			"name":     "john",
+			"birthday": "2001-02-03",
+			"address":  map[string]interface{}{"city": "Dubai"},
+			"tags":     []interface{}{"a", "b"},
+		}, nil},
+		{"invalid", map[string]interface{}{
+			"age":      "old",
+			"active":   "yes please",
+			"name":     12,
+			"birthday": "yesterday",
+			"address":  "Dubai",
+			"tags":     []interface{}{"a", "b", "c"},
+		}, []string{"active", "address", "age", "birthday", "name", "tags"}},
+	})
+	errs := schematics.Validate(map[string]interface{}{"age": "old", "tags": []interface{}{"a", "b", "c"}})
+	if errs.Messages["age"].Validator != "is-type" || errs.Messages["tags"].Validator != "ArrayLengthMax" {
+		t.Fatalf("unexpected errors %v", errs.GetStrings("en", "%target: %message"))
+	}
+
+	result, errs := schematics.Operate(map[string]interface{}{"age": "42", "active": "false"})
+	if errs.HasErrors() {
+		t.Fatal(errs.Messages)
+	}
+	operated := *result.(*map[string]interface{})
+	if operated["age"] != 42 || operated["active"] != false {
+		t.Fatalf("values should be coerced, got %v", operated)
+	}
+
+	schematics.Schema.Fields["name"] = Field{Type: "text"}
+	if _, err := schematics.Compile(); err == nil {
+		t.Fatal("unknown types should not compile")
+	}
+}
+
+func TestStrictSchemaReportsUnknownFields(t *testing.T) {
+	data := map[string]interface{}{
+		"id":      "1",
+		"emial":   "a@b.co",
+		"email":   "a@b.co",
+		"address": map[string]interface{}{"city": "Dubai"},
+		"phones":  []interface{}{map[string]interface{}{"number": "1", "kind": "home"}},
+	}
+	for _, test := range []struct {
+		name       string
+		additional interface{}
+		expected   []string
+	}{
+		{"allowed by default", nil, nil},
+		{"not allowed", false, []string{"emial", "phones.0.kind"}},
+	} {
+		schema := map[string]interface{}{
+			"version": "0",
+			"fields": map[string]interface{}{
+				"email":           map[string]interface{}{"validators": map[string]interface{}{"IsEmail": map[string]interface{}{}}},
+				"address":         map[string]interface{}{},
+				"phones.*.number": map[string]interface{}{},
+			},
+		}
+		if test.additional != nil {
+			schema["additional_fields"] = test.additional
+		}
+		schematics := loadSchema(t, schema)
+		schematics.ArrayIdKey = "id"
+		errs := schematics.Validate(data)
+		if got := errorTargets(errs); strings.Join(got, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%s: expected the unknown fields %v, got %v", test.name, test.expected, errs.GetStrings("en", "%target: %message"))
+		}
+		for _, target := range test.expected {
+			if validator := errs.Messages[errorHandler.Target(target)].Validator; validator != "unknown-field" {
+				t.Errorf("%s: unexpected validator %s on %s", test.name, validator, target)
+			}
+		}
+	}
+}
+
+func TestNestedFieldsAndItems(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"orders": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"fields": map[string]interface{}{
+						"id": map[string]interface{}{"required": true},
+						"items": map[string]interface{}{
+							"items": map[string]interface{}{
+								"fields": map[string]interface{}{
+									"options": map[string]interface{}{
+										"items": map[string]interface{}{
+											"type":      "string",
+											"operators": []interface{}{map[string]interface{}{"name": "LowerCase"}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]interface{}{
+		"orders": []interface{}{
+			map[string]interface{}{"id": 1, "items": []interface{}{map[string]interface{}{"options": []interface{}{"RED", "Large"}}}},
+		},
+	}
+	runValidationCases(t, schematics, []validationCase{
+		{"valid", valid, nil},
+		{"nested errors", map[string]interface{}{
+			"orders": []interface{}{
+				map[string]interface{}{"id": 1, "items": []interface{}{map[string]interface{}{"options": []interface{}{"red"}}}},
+				map[string]interface{}{"items": []interface{}{map[string]interface{}{"options": []interface{}{"red"}}, map[string]interface{}{"options": []interface{}{"red", 2}}}},
+			},
+		}, []string{"orders.1.id", "orders.1.items.1.options.1"}},
+		// an empty object in the array is still an element, its required fields are missing
+		{"empty object", map[string]interface{}{"orders": []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{}}}, []string{"orders.1.id"}},
+	})
+
+	result, errs := schematics.Operate(valid)
+	if errs.HasErrors() {
+		t.Fatal(errs.Messages)
+	}
+	orders := (*result.(*map[string]interface{}))["orders"].([]interface{})
+	options := orders[0].(map[string]interface{})["items"].([]interface{})[0].(map[string]interface{})["options"].([]interface{})
+	if options[0] != "red" || options[1] != "large" {
+		t.Fatalf("the operators of the nested items should run, got %v", options)
+	}
+}
+
+func TestDocumentValidators(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"password_confirmation": map[string]interface{}{
+				"validators": map[string]interface{}{"EqualsField": map[string]interface{}{"attributes": map[string]interface{}{"field": "password"}}},
+			},
+			"end_date": map[string]interface{}{
+				"validators": map[string]interface{}{"DateAfterField": map[string]interface{}{"attributes": map[string]interface{}{"field": "start_date"}}},
+			},
+			"max": map[string]interface{}{
+				"validators": map[string]interface{}{"GreaterThanField": map[string]interface{}{"attributes": map[string]interface{}{"field": "min", "or_equal": true}}},
+			},
+			"items.*.end": map[string]interface{}{
+				"validators": map[string]interface{}{"GreaterThanField": map[string]interface{}{"attributes": map[string]interface{}{"field": "items.*.start"}}},
+			},
+		},
+	})
+	runValidationCases(t, schematics, []validationCase{
+		{"valid", map[string]interface{}{
+			"password":              "secret",
+			"password_confirmation": "secret",
+			"start_date":            "2024-01-01",
+			"end_date":              "2024-02-01",
+			"min":                   5,
+			"max":                   5.0,
+			"items":                 []interface{}{map[string]interface{}{"start": 1, "end": 2}, map[string]interface{}{"start": 10, "end": 20}},
+		}, nil},
+		// items.0.end is compared with its own start
+		{"invalid", map[string]interface{}{
+			"password":              "secret",
+			"password_confirmation": "Secret",
+			"start_date":            "2024-02-01",
+			"end_date":              "2024-01-01",
+			"min":                   5,
+			"max":                   4,
+			"items":                 []interface{}{map[string]interface{}{"start": 1, "end": 2}, map[string]interface{}{"start": 10, "end": 3}},
+		}, []string{"end_date", "items.1.end", "max", "password_confirmation"}},
+	})
+}
+
+func TestWhenExpressions(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"zip":   map[string]interface{}{"required": true, "when": `country == "US" && age >= 18`},
+			"state": map[string]interface{}{"required": true, "when": `country in ["US", "CA"] && !(tags.0 == 'skip')`},
+			"phone": map[string]interface{}{
+				"required":   true,
+				"conditions": map[string]interface{}{"FieldIsProvided": map[string]interface{}{"attributes": map[string]interface{}{"shouldBeProvided": "contact.sms"}}},
+			},
+		},
+	})
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	runValidationCases(t, schematics, []validationCase{
+		{"adult in the US", map[string]interface{}{"country": "US", "age": 21}, []string{"state", "zip"}},
+		{"skipped state", map[string]interface{}{"country": "US", "age": "21", "tags": []interface{}{"skip"}}, nil},
+		{"state in CA", map[string]interface{}{"country": "CA", "age": 30, "state": "ON"}, nil},
+		{"sms contact", map[string]interface{}{"country": "DE", "contact": map[string]interface{}{"sms": true}}, []string{"phone"}},
+	})
+
+	// the expressions are checked when the schema is loaded, even without FailOnLintErrors
+	tests := []struct {
+		name     string
+		fields   map[string]interface{}
+		expected string
+	}{
+		{"when", map[string]interface{}{"zip": map[string]interface{}{"when": `country == `}},
+			`fields[zip].when: invalid expression "country == ": unexpected end of expression at 11`},
+		{"nested when", map[string]interface{}{"orders": map[string]interface{}{"items": map[string]interface{}{"fields": map[string]interface{}{
+			"id": map[string]interface{}{"when": `(type == "a"`},
+		}}}}, `fields[orders].items.fields[id].when: invalid expression "(type == \"a\"": `},
+		{"expression condition", map[string]interface{}{"zip": map[string]interface{}{"conditions": map[string]interface{}{
+			"AnyOf": map[string]interface{}{"attributes": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"name": "Expression", "attributes": map[string]interface{}{"expression": `age >`}},
+			}}},
+		}}}, `fields[zip].conditions[AnyOf].attributes.conditions[0].attributes.expression: invalid expression "age >": `},
+	}
+	for _, test := range tests {
+		var invalid Schematics
+		err := invalid.LoadMap(map[string]interface{}{"version": "0", "fields": test.fields})
+		if err == nil || !strings.HasPrefix(err.Error(), "the schema has invalid expressions: "+test.expected) {
+			t.Errorf("%s: expected the invalid expression to fail loading, got %v", test.name, err)
+		}
+	}
+
+	schematics.Schema.Fields["zip"] = Field{When: `country == `}
+	if _, err := schematics.Compile(); err == nil || !strings.Contains(err.Error(), "when on zip") {
+		t.Fatalf("expected the invalid expression to fail the compilation, got %v", err)
+	}
+}
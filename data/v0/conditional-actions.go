@@ -0,0 +1,122 @@
+package v0
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConditionActions are the actions of a ConditionalAction, the ones ending with : are followed by the name of a validator or an operator,
+// e.g. enable:IsEmail. Validators and operators of the field that are enabled or operated by an action only run when the action is taken
+var ConditionActions = []string{"require", "optional", "skip", "enable:", "disable:", "operate:"}
+
+// parseAction returns the kind of the action and the name that follows it
+func parseAction(action string) (string, string, error) {
+	kind, name, hasName := strings.Cut(strings.TrimSpace(action), ":")
+	switch kind {
+	case "require", "optional", "skip":
+		if hasName {
+			return "", "", fmt.Errorf("action %s does not take a name", action)
+		}
+		return kind, "", nil
+	case "enable", "disable", "operate":
+		if name == "" {
+			return "", "", fmt.Errorf("action %s needs a name, e.g. %s:IsEmail", action, kind)
+		}
+		return kind, name, nil
+	}
+	return "", "", fmt.Errorf("unknown action %s", action)
+}
+
+// fieldState is the outcome of the conditions of a field for a single document
+type fieldState struct {
+	skip     bool
+	required bool
+	// validators are switched on or off by the actions, operators are switched on
+	validators map[string]bool
+	operators  map[string]bool
+}
+
+func (s *fieldState) apply(action string) {
+	kind, name, err := parseAction(action)
+	if err != nil {
+		return
+	}
+	switch kind {
+	case "require":
+		s.required = true
+	case "optional":
+		s.required = false
+	case "skip":
+		s.skip = true
+	case "enable", "disable":
+		if s.validators == nil {
+			s.validators = make(map[string]bool)
+		}
+		s.validators[name] = kind == "enable"
+	case "operate":
+		if s.operators == nil {
+			s.operators = make(map[string]bool)
+		}
+		s.operators[name] = true
+	}
+}
+
+func (s fieldState) runsValidator(validator compiledValidator) bool {
+	if enabled, switched := s.validators[validator.name]; switched {
+		return enabled
+	}
+	return !validator.conditional
+}
+
+func (s fieldState) runsOperator(operator compiledOperator) bool {
+	return !operator.conditional || s.operators[operator.name]
+}
+
+// compileActions checks the actions of the conditions, the validators and operators they name only run when the action is taken.
+// An operator that is not on the field is taken from the registered operators
+func (s *Schematics) compileActions(cf *compiledField) []string {
+	var unresolved []string
+	for _, condition := range cf.conditions {
+		if condition.action == nil {
+			continue
+		}
+		for _, action := range append(append([]string(nil), condition.action.Success...), condition.action.Error...) {
+			kind, name, err := parseAction(action)
+			if err != nil {
+				unresolved = append(unresolved, fmt.Sprintf("%v in condition %s on %s", err, condition.name, cf.target))
+				continue
+			}
+			switch kind {
+			case "enable", "disable":
+				found := false
+				for i := range cf.validators {
+					if cf.validators[i].name == name {
+						found = true
+						cf.validators[i].conditional = cf.validators[i].conditional || kind == "enable"
+					}
+				}
+				if !found {
+					unresolved = append(unresolved, fmt.Sprintf("validator %s of the action %s on %s", name, action, cf.target))
+				}
+			case "operate":
+				found := false
+				for i := range cf.operators {
+					if cf.operators[i].name == name {
+						found = true
+						cf.operators[i].conditional = true
+					}
+				}
+				if found {
+					continue
+				}
+				fn, ok := s.Operators.OpFunctions[name]
+				if !ok {
+					unresolved = append(unresolved, fmt.Sprintf("operator %s of the action %s on %s", name, action, cf.target))
+					continue
+				}
+				cf.operators = append(cf.operators, compiledOperator{name: name, fn: fn, conditional: true})
+			}
+		}
+	}
+	return unresolved
+}
@@ -0,0 +1,129 @@
+package v0
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConditionLibraryAndCombinators(t *testing.T) {
+	mapped := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"zip": map[string]interface{}{
+				"required": true,
+				"conditions": map[string]interface{}{
+					"AllOf": map[string]interface{}{"attributes": map[string]interface{}{"conditions": map[string]interface{}{
+						"FieldEquals":      map[string]interface{}{"attributes": map[string]interface{}{"field": "country", "value": "US"}},
+						"FieldGreaterThan": map[string]interface{}{"attributes": map[string]interface{}{"field": "age", "value": 18, "or_equal": true}},
+					}}},
+				},
+			},
+		},
+	})
+	if _, err := mapped.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	runValidationCases(t, mapped, []validationCase{
+		{"adult", map[string]interface{}{"country": "US", "age": 18}, []string{"zip"}},
+		{"minor", map[string]interface{}{"country": "US", "age": 17}, nil},
+	})
+
+	listed := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"vat": map[string]interface{}{
+				"required": true,
+				"conditions": map[string]interface{}{
+					"AnyOf": map[string]interface{}{"attributes": map[string]interface{}{"conditions": []interface{}{
+						map[string]interface{}{"name": "FieldIn", "attributes": map[string]interface{}{"field": "country", "values": []interface{}{"DE", "FR"}}},
+						map[string]interface{}{"name": "FieldMatchesRegex", "attributes": map[string]interface{}{"field": "company", "regex": "(?i)gmbh$"}},
+					}}},
+					"Not": map[string]interface{}{"attributes": map[string]interface{}{"conditions": []interface{}{
+						map[string]interface{}{"name": "AnyOf", "attributes": map[string]interface{}{"conditions": []interface{}{
+							map[string]interface{}{"name": "FieldIsMissing", "attributes": map[string]interface{}{"field": "company"}},
+							map[string]interface{}{"name": "FieldIsEmpty", "attributes": map[string]interface{}{"field": "company"}},
+						}}},
+					}}},
+				},
+			},
+		},
+	})
+	if _, err := listed.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	runValidationCases(t, listed, []validationCase{
+		{"country in the list", map[string]interface{}{"country": "DE", "company": "Acme"}, []string{"vat"}},
+		{"company matches", map[string]interface{}{"country": "US", "company": "Acme GmbH"}, []string{"vat"}},
+		{"neither", map[string]interface{}{"country": "US", "company": "Acme"}, nil},
+		{"empty company", map[string]interface{}{"country": "DE", "company": ""}, nil},
+		{"missing company", map[string]interface{}{"country": "DE"}, nil},
+	})
+
+	unknown := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{"vat": map[string]interface{}{
+			"conditions": map[string]interface{}{"AllOf": map[string]interface{}{"attributes": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"name": "FieldIsGreen"},
+			}}}},
+		}},
+	})
+	if _, err := unknown.Compile(); err == nil || !strings.Contains(err.Error(), "condition FieldIsGreen on vat") {
+		t.Fatalf("expected the nested condition to be unresolved, got %v", err)
+	}
+}
+
+func TestConditionalActions(t *testing.T) {
+	isBusiness := func(success []interface{}, failure []interface{}) map[string]interface{} {
+		return map[string]interface{}{"FieldEquals": map[string]interface{}{
+			"attributes": map[string]interface{}{"field": "type", "value": "business"},
+			"action":     map[string]interface{}{"success": success, "error": failure},
+		}}
+	}
+	schematics := loadSchema(t, map[string]interface{}{
+		"version":            "0",
+		"collect_all_errors": true,
+		"fields": map[string]interface{}{
+			"tax_id": map[string]interface{}{
+				"validators": map[string]interface{}{"MinLengthAllowed": map[string]interface{}{"attributes": map[string]interface{}{"min": 5}}},
+				"conditions": isBusiness([]interface{}{"require", "enable:MinLengthAllowed"}, nil),
+			},
+			"email": map[string]interface{}{
+				"required":   true,
+				"validators": map[string]interface{}{"IsEmail": map[string]interface{}{}},
+				"conditions": isBusiness(nil, []interface{}{"optional", "disable:IsEmail"}),
+			},
+			"name": map[string]interface{}{
+				"operators":  []interface{}{map[string]interface{}{"name": "Trim"}},
+				"conditions": isBusiness([]interface{}{"operate:UpperCase"}, nil),
+			},
+		},
+	})
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	runValidationCases(t, schematics, []validationCase{
+		{"business rules", map[string]interface{}{"type": "business", "tax_id": "123", "email": "not-an-email"}, []string{"email[IsEmail]", "tax_id[MinLengthAllowed]"}},
+		{"required tax_id", map[string]interface{}{"type": "business", "email": "a@b.co"}, []string{"tax_id"}},
+		{"person rules", map[string]interface{}{"type": "person", "tax_id": "123", "email": "not-an-email"}, nil},
+	})
+
+	for data, expected := range map[string]string{"business": "ACME", "person": "acme"} {
+		result, errs := schematics.Operate(map[string]interface{}{"type": data, "name": " acme "})
+		if errs.HasErrors() {
+			t.Fatal(errs.Messages)
+		}
+		if name := (*result.(*map[string]interface{}))["name"]; name != expected {
+			t.Errorf("expected %s for %s, got %v", expected, data, name)
+		}
+	}
+
+	invalid := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{"tax_id": map[string]interface{}{
+			"conditions": isBusiness([]interface{}{"enable:IsEmail", "explode"}, nil),
+		}},
+	})
+	if _, err := invalid.Compile(); err == nil || !strings.Contains(err.Error(), "validator IsEmail of the action enable:IsEmail") || !strings.Contains(err.Error(), "unknown action explode") {
+		t.Fatalf("expected the invalid actions to fail the compilation, got %v", err)
+	}
+}
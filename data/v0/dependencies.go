@@ -0,0 +1,125 @@
+package v0
+
+import (
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"strings"
+)
+
+// DependencyOrder returns the targets of the fields in the order they are validated, every field comes after the fields in its depends_on.
+// Dependencies that are not fields are left out of the order, circular dependencies are returned as an error
+func DependencyOrder(fields map[TargetKey]Field) ([]TargetKey, error) {
+	order := make([]TargetKey, 0, len(fields))
+	// visited is 1 while the dependencies of the target are visited and 2 once the target is in the order
+	visited := make(map[TargetKey]int, len(fields))
+	var path []TargetKey
+
+	var visit func(target TargetKey) error
+	visit = func(target TargetKey) error {
+		switch visited[target] {
+		case 1:
+			var cycle []string
+			for i := len(path) - 1; i >= 0; i-- {
+				cycle = append([]string{string(path[i])}, cycle...)
+				if path[i] == target {
+					break
+				}
+			}
+			return fmt.Errorf("circular depends_on: %s -> %s", strings.Join(cycle, " -> "), target)
+		case 2:
+			return nil
+		}
+		visited[target] = 1
+		path = append(path, target)
+		for _, dependency := range fields[target].DependsOn {
+			if _, isField := fields[TargetKey(dependency)]; !isField {
+				continue
+			}
+			if err := visit(TargetKey(dependency)); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		visited[target] = 2
+		order = append(order, target)
+		return nil
+	}
+
+	for _, target := range sortedTargets(fields) {
+		if err := visit(target); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// CheckDependencies returns an error when the depends_on of the fields, or of the fields nested in them, are circular
+func (s Schema) CheckDependencies() error {
+	return checkDependencies(s.Fields, "")
+}
+
+func checkDependencies(fields map[TargetKey]Field, parent string) error {
+	if _, err := DependencyOrder(fields); err != nil {
+		if parent != "" {
+			return fmt.Errorf("%w in %s", err, parent)
+		}
+		return err
+	}
+	for _, target := range sortedTargets(fields) {
+		name := string(target)
+		if parent != "" {
+			name = parent + "." + name
+		}
+		field := fields[target]
+		if err := checkDependencies(field.Fields, name); err != nil {
+			return err
+		}
+		if field.Items != nil {
+			if err := checkDependencies(field.Items.Fields, name+".*"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dependencyError is the error of the field when the fields it depends on are not provided, or are validated with errors
+func (c *CompiledSchematics) dependencyError(field Field, fields map[TargetKey]Field, flatData map[string]interface{}, failed map[string]bool, target string, id *string) *errorHandler.Error {
+	var missing, invalid []string
+	for _, dependency := range field.DependsOn {
+		dependencyField, isField := fields[TargetKey(dependency)]
+		if isField && !dependencyField.Provided {
+			missing = append(missing, dependency)
+			continue
+		}
+		if !isField {
+			if _, exists := flatData[dependency]; !exists && len(utils.FindMatchingKeys(flatData, dependency, c.separator)) == 0 {
+				missing = append(missing, dependency)
+			}
+			continue
+		}
+		if failed[dependency] {
+			invalid = append(invalid, dependency)
+		}
+	}
+	if len(missing) == 0 && len(invalid) == 0 {
+		return nil
+	}
+
+	var messages []string
+	if len(missing) > 0 {
+		messages = append(messages, "missing dependencies: "+strings.Join(missing, ", "))
+	}
+	if len(invalid) > 0 {
+		messages = append(messages, "invalid dependencies: "+strings.Join(invalid, ", "))
+	}
+	var errorMessage errorHandler.Error
+	errorMessage.DataTarget = target
+	errorMessage.Validator = "depends-on"
+	if id != nil {
+		errorMessage.ID = *id
+	}
+	errorMessage.AddMessage("en", strings.Join(messages, "; "))
+	return &errorMessage
+}
@@ -0,0 +1,61 @@
+package v0
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDependsOn(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"confirmation": map[string]interface{}{
+				"depends_on": []interface{}{"password"},
+				"validators": map[string]interface{}{"EqualsField": map[string]interface{}{"attributes": map[string]interface{}{"field": "password"}}},
+			},
+			"password": map[string]interface{}{
+				"validators": map[string]interface{}{"MinLengthAllowed": map[string]interface{}{"attributes": map[string]interface{}{"min": 8}}},
+			},
+			"email": map[string]interface{}{"depends_on": []interface{}{"user.name"}},
+		},
+	})
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		expected []string
+	}{
+		{"valid", map[string]interface{}{"password": "long enough", "confirmation": "long enough", "email": "a@b.co", "user": map[string]interface{}{"name": "a"}}, nil},
+		{"invalid dependency", map[string]interface{}{"password": "short", "confirmation": "short"}, []string{"password: ", "confirmation: invalid dependencies: password"}},
+		{"missing dependency", map[string]interface{}{"confirmation": "long enough"}, []string{"confirmation: missing dependencies: password"}},
+		{"missing dependency outside the schema", map[string]interface{}{"email": "a@b.co"}, []string{"email: missing dependencies: user.name"}},
+	}
+	for _, test := range tests {
+		got := errorStrings(schematics.Validate(test.data))
+		if len(got) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+			continue
+		}
+		for i := range got {
+			if !strings.HasPrefix(got[i], test.expected[i]) {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+			}
+		}
+	}
+
+	var circular Schematics
+	err := circular.LoadMap(map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"a": map[string]interface{}{"depends_on": []interface{}{"b"}},
+			"b": map[string]interface{}{"depends_on": []interface{}{"c"}},
+			"c": map[string]interface{}{"depends_on": []interface{}{"a"}},
+		},
+	})
+	if err == nil || err.Error() != "circular depends_on: a -> b -> c -> a" {
+		t.Fatalf("expected the circular dependencies to fail loading, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/validators"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldTypes are the types that can be declared on a field, the value is checked against the type before the validators run
+var FieldTypes = []string{"string", "number", "integer", "boolean", "date", "object", "array"}
+
+func knownFieldType(fieldType string) bool {
+	for _, t := range FieldTypes {
+		if t == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkType returns an error when the value is not of the field type
+func checkType(fieldType string, value interface{}) error {
+	var ok bool
+	switch fieldType {
+	case "string":
+		_, ok = value.(string)
+	case "number":
+		_, ok = toFloat64(value)
+	case "integer":
+		number, isNumber := toFloat64(value)
+		ok = isNumber && number == math.Trunc(number)
+	case "boolean":
+		_, ok = value.(bool)
+	case "date":
+		if str, isString := value.(string); isString {
+			ok = validators.InterfaceToDate(str) != nil
+		}
+	case "object":
+		ok = value != nil && reflect.TypeOf(value).Kind() == reflect.Map
+	case "array":
+		if value != nil {
+			kind := reflect.TypeOf(value).Kind()
+			ok = kind == reflect.Slice || kind == reflect.Array
+		}
+	default:
+		return nil
+	}
+	if !ok {
+		return fmt.Errorf("value should be of type %s", fieldType)
+	}
+	return nil
+}
+
+// coerceValue converts the string value to the field type, the value is returned as it is when it can not be converted
+func coerceValue(fieldType string, value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+	str = strings.TrimSpace(str)
+	switch fieldType {
+	case "number":
+		if number, err := strconv.ParseFloat(str, 64); err == nil {
+			return number
+		}
+	case "integer":
+		if number, err := strconv.ParseInt(str, 10, 64); err == nil {
+			return int(number)
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(str); err == nil {
+			return boolean
+		}
+	case "object":
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(str), &obj); err == nil && obj != nil {
+			return obj
+		}
+	case "array":
+		var arr []interface{}
+		if err := json.Unmarshal([]byte(str), &arr); err == nil && arr != nil {
+			return arr
+		}
+	}
+	return value
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,298 @@
+package v0
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// jsonSchemaFormats are the validators of the formats of JSON Schema
+var jsonSchemaFormats = map[string]string{
+	"email":     "IsEmail",
+	"uri":       "IsURL",
+	"url":       "IsURL",
+	"uuid":      "IsValidUuid",
+	"date":      "IsValidDate",
+	"date-time": "IsValidDate",
+}
+
+// jsonSchemaRootKeywords are the keywords of the root schema that are translated, the root is not a field so the
+// keywords of values do not apply to it
+var jsonSchemaRootKeywords = []string{"type", "title", "description", "required", "properties", "items", "dependentRequired", "dependencies", "additionalProperties"}
+
+// jsonSchemaFieldKeywords are the keywords of the schemas of properties and items that are translated
+var jsonSchemaFieldKeywords = append([]string{"default", "nullable", "minLength", "maxLength", "minimum", "maximum", "minItems",
+	"maxItems", "pattern", "format", "enum", "const"}, jsonSchemaRootKeywords...)
+
+// jsonSchemaAnnotations are the keywords that do not need to be translated
+var jsonSchemaAnnotations = []string{"$schema", "$id", "$comment", "$defs", "definitions", "examples", "readOnly", "writeOnly", "deprecated"}
+
+// ImportJsonSchemaFile loads the schematics from a JSON Schema file, see ImportJsonSchema
+func (s *Schematics) ImportJsonSchemaFile(path string) ([]Diagnostic, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		s.Logging.ERROR("Failed to load the json schema file", err)
+		return nil, err
+	}
+	return s.importJsonSchema(content, filepath.Dir(path))
+}
+
+// ImportJsonSchema loads the schematics from a JSON Schema (draft-07 or 2020-12) document, the keywords that are not
+// translated to targets and validators are returned as warnings with their path in the document, e.g. #/properties/age/oneOf
+func (s *Schematics) ImportJsonSchema(content []byte) ([]Diagnostic, error) {
+	return s.importJsonSchema(content, "")
+}
+
+func (s *Schematics) importJsonSchema(content []byte, dir string) ([]Diagnostic, error) {
+	s.Configs()
+	content, err := utils.ResolveReferencesKeepingCycles(content, dir, "")
+	if err != nil {
+		s.Logging.ERROR("Failed to resolve the references of the json schema", err)
+		return nil, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		s.Logging.ERROR("Invalid json schema", err)
+		return nil, err
+	}
+	if s.Separator == "" {
+		s.Separator = "."
+	}
+	fields, report := JsonSchemaFields(document, s.Separator)
+	schema := Schema{Version: "0", Fields: fields}
+	if additional, ok := document["additionalProperties"].(bool); ok && !additional {
+		schema.Strict = true
+	}
+	s.Logging.DEBUG("Schema Imported From JSON Schema: ", schema)
+	s.Schema = schema
+	s.Validators.BasicValidators()
+	s.Operators.LoadBasicOperations()
+	s.Conditions.BasicConditions()
+	if s.Locale == "" {
+		s.Locale = "en"
+	}
+	return report, nil
+}
+
+// JsonSchemaFields translates the JSON Schema to fields, the properties of objects become the fields of their field and
+// the items of arrays become its items, so a required property is only required when its object is provided. required,
+// type, nullable, title, description, default, dependentRequired, the length, range and item count keywords, pattern,
+// enum, const and format become validators and attributes of the fields, every other keyword and the recursive $refs
+// are returned as warnings
+func JsonSchemaFields(document map[string]interface{}, separator string) (map[TargetKey]Field, []Diagnostic) {
+	importer := jsonSchemaImporter{separator: separator}
+	fields := importer.object(document, "#", true)
+	if items := importer.items(document, "#"); items != nil {
+		fields["*"] = *items
+	}
+	return fields, importer.report
+}
+
+type jsonSchemaImporter struct {
+	separator string
+	report    []Diagnostic
+}
+
+func (i *jsonSchemaImporter) warn(path string, format string, args ...interface{}) {
+	i.report = append(i.report, Diagnostic{Path: path, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)})
+}
+
+// object translates the properties of the schema to fields, the keywords of the value itself are translated by field
+func (i *jsonSchemaImporter) object(schema map[string]interface{}, path string, root bool) map[TargetKey]Field {
+	fields := map[TargetKey]Field{}
+	required := map[string]bool{}
+	if names, ok := schema["required"].([]interface{}); ok {
+		for _, name := range names {
+			if n, ok := name.(string); ok {
+				required[n] = true
+			}
+		}
+	}
+	dependencies := map[string][]string{}
+	for _, keyword := range []string{"dependentRequired", "dependencies"} {
+		entries, _ := schema[keyword].(map[string]interface{})
+		for _, name := range sortedKeywords(entries) {
+			list, ok := entries[name].([]interface{})
+			if !ok {
+				i.warn(fmt.Sprintf("%s/%s/%s", path, keyword, name), "only the dependencies on other properties are translated")
+				continue
+			}
+			for _, dependency := range list {
+				if d, ok := dependency.(string); ok {
+					dependencies[name] = append(dependencies[name], d)
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range sortedKeywords(properties) {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			i.warn(path+"/properties/"+name, "only object schemas are translated")
+			continue
+		}
+		if i.separator != "" && strings.Contains(name, i.separator) {
+			i.warn(path+"/properties/"+name, "the name contains the separator %s, it is not translated", i.separator)
+			continue
+		}
+		fields[TargetKey(name)] = i.field(property, path+"/properties/"+name, required[name], dependencies[name])
+	}
+	for name := range required {
+		if _, declared := properties[name]; !declared {
+			fields[TargetKey(name)] = Field{IsRequired: true, DependsOn: dependencies[name]}
+		}
+	}
+
+	if additional, exists := schema["additionalProperties"]; exists {
+		if allowed, ok := additional.(bool); !ok || (!allowed && !root) {
+			i.warn(path+"/additionalProperties", "only \"additionalProperties\": false on the root schema is translated, it makes the schema strict")
+		}
+	}
+	if root {
+		for _, keyword := range sortedKeywords(schema) {
+			if !utils.StringInStrings(keyword, jsonSchemaRootKeywords) && !i.known(keyword) {
+				i.warn(path+"/"+keyword, "%s is not translated", keyword)
+			}
+		}
+	}
+	return fields
+}
+
+// items translates the items of the array schema to the field of every element
+func (i *jsonSchemaImporter) items(schema map[string]interface{}, path string) *Field {
+	switch items := schema["items"].(type) {
+	case map[string]interface{}:
+		field := i.field(items, path+"/items", false, nil)
+		return &field
+	case nil:
+	default:
+		i.warn(path+"/items", "only an object schema is translated for the items")
+	}
+	return nil
+}
+
+// field translates the schema of the value to a field with the fields of its properties and its items
+func (i *jsonSchemaImporter) field(schema map[string]interface{}, path string, required bool, dependencies []string) Field {
+	field := Field{
+		IsRequired: required,
+		DependsOn:  dependencies,
+		Validators: map[string]Constant{},
+	}
+	if title, ok := schema["title"].(string); ok {
+		field.DisplayName = title
+	}
+	if description, ok := schema["description"].(string); ok {
+		field.Description = description
+	}
+	if value, exists := schema["default"]; exists {
+		field.Default = value
+	}
+	if nullable, ok := schema["nullable"].(bool); ok {
+		field.Nullable = nullable
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		i.fieldType(&field, t, path)
+	case []interface{}:
+		var types []string
+		for _, item := range t {
+			if name, ok := item.(string); ok && name != "null" {
+				types = append(types, name)
+			} else if ok {
+				field.Nullable = true
+			}
+		}
+		if len(types) == 1 {
+			i.fieldType(&field, types[0], path)
+		} else if len(types) > 1 {
+			i.warn(path+"/type", "only one type besides null is translated")
+		}
+	}
+
+	validator := func(name string, attributes map[string]interface{}) {
+		field.Validators[name] = Constant{Attributes: attributes}
+	}
+	if minLength, exists := schema["minLength"]; exists {
+		validator("MinLengthAllowed", map[string]interface{}{"min": minLength})
+	}
+	if maxLength, exists := schema["maxLength"]; exists {
+		validator("MaxLengthAllowed", map[string]interface{}{"max": maxLength})
+	}
+	if minimum, exists := schema["minimum"]; exists {
+		validator("MinAllowed", map[string]interface{}{"min": minimum})
+	}
+	if maximum, exists := schema["maximum"]; exists {
+		validator("MaxAllowed", map[string]interface{}{"max": maximum})
+	}
+	if minItems, exists := schema["minItems"]; exists {
+		validator("ArrayLengthMin", map[string]interface{}{"min": minItems})
+	}
+	if maxItems, exists := schema["maxItems"]; exists {
+		validator("ArrayLengthMax", map[string]interface{}{"max": maxItems})
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		validator("MatchRegex", map[string]interface{}{"regex": pattern})
+	}
+	if format, ok := schema["format"].(string); ok {
+		if name, known := jsonSchemaFormats[format]; known {
+			validator(name, nil)
+		} else {
+			i.warn(path+"/format", "the format %s is not translated", format)
+		}
+	}
+	options, hasOptions := schema["enum"].([]interface{})
+	if value, exists := schema["const"]; exists {
+		options, hasOptions = []interface{}{value}, true
+	}
+	if hasOptions {
+		if stringOptions(options) {
+			validator("StringInOptions", map[string]interface{}{"options": options})
+		} else {
+			i.warn(path, "only enum and const with strings are translated")
+		}
+	}
+	if len(field.Validators) == 0 {
+		field.Validators = nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		i.warn(path+"/$ref", "the recursive reference %s is not translated", ref)
+	}
+
+	if fields := i.object(schema, path, false); len(fields) > 0 {
+		field.Fields = fields
+	}
+	field.Items = i.items(schema, path)
+	for _, keyword := range sortedKeywords(schema) {
+		if keyword != "$ref" && !utils.StringInStrings(keyword, jsonSchemaFieldKeywords) && !i.known(keyword) {
+			i.warn(path+"/"+keyword, "%s is not translated", keyword)
+		}
+	}
+	return field
+}
+
+func (i *jsonSchemaImporter) fieldType(field *Field, jsonType string, path string) {
+	if !knownFieldType(jsonType) || jsonType == "date" {
+		i.warn(path+"/type", "the type %s is not translated", jsonType)
+		return
+	}
+	field.Type = jsonType
+}
+
+// known tells if the keyword is an annotation that does not need to be translated
+func (i *jsonSchemaImporter) known(keyword string) bool {
+	return utils.StringInStrings(keyword, jsonSchemaAnnotations) || strings.HasPrefix(keyword, "x-")
+}
+
+func stringOptions(options []interface{}) bool {
+	for _, option := range options {
+		if _, ok := option.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,306 @@
+package v0
+
+import (
+	"encoding/json"
+	"github.com/ashbeelghouri/jsonschematics/validators"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JsonSchemaDraft is the $schema of the exported JSON Schema
+const JsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaKeywords map the basic validators to JSON Schema keywords, a validator returns nil when its attributes can
+// not be mapped and is exported in x-validators like the validators that are not in the map
+var jsonSchemaKeywords = map[string]func(attributes map[string]interface{}) map[string]interface{}{
+	"IsString":               fixedKeywords(map[string]interface{}{"type": "string"}),
+	"NotEmpty":               fixedKeywords(map[string]interface{}{"type": "string", "pattern": `\S`}),
+	"IsEmail":                fixedKeywords(map[string]interface{}{"type": "string", "format": "email"}),
+	"IsURL":                  fixedKeywords(map[string]interface{}{"type": "string", "format": "uri"}),
+	"IsValidUuid":            fixedKeywords(map[string]interface{}{"type": "string", "format": "uuid"}),
+	"IsHttps":                fixedKeywords(map[string]interface{}{"type": "string", "format": "uri", "pattern": "^https://"}),
+	"LeastOneUpperCase":      fixedKeywords(map[string]interface{}{"type": "string", "pattern": "[A-Z]"}),
+	"LeastOneLowerCase":      fixedKeywords(map[string]interface{}{"type": "string", "pattern": "[a-z]"}),
+	"LeastOneDigit":          fixedKeywords(map[string]interface{}{"type": "string", "pattern": `\d`}),
+	"MaxLengthAllowed":       attributeKeywords("max", "maxLength"),
+	"MinLengthAllowed":       attributeKeywords("min", "minLength"),
+	"InBetweenLengthAllowed": attributeKeywords("min", "minLength", "max", "maxLength"),
+	"MatchRegex":             attributeKeywords("regex", "pattern"),
+	"LIKE": func(attributes map[string]interface{}) map[string]interface{} {
+		if pattern, ok := attributes["pattern"].(string); ok {
+			return map[string]interface{}{"type": "string", "pattern": validators.LikeToRegex(pattern)}
+		}
+		return nil
+	},
+	"IsNumber":          fixedKeywords(map[string]interface{}{"type": "number"}),
+	"IsFloat":           fixedKeywords(map[string]interface{}{"type": "number"}),
+	"IsInteger":         fixedKeywords(map[string]interface{}{"type": "integer"}),
+	"MaxAllowed":        attributeKeywords("max", "maximum"),
+	"MinAllowed":        attributeKeywords("min", "minimum"),
+	"InBetween":         attributeKeywords("min", "minimum", "max", "maximum"),
+	"IsGreaterThanZero": fixedKeywords(map[string]interface{}{"minimum": 0}),
+	"IsValidDate":       fixedKeywords(map[string]interface{}{"type": "string"}),
+	"ArrayLengthMax":    attributeKeywords("max", "maxItems"),
+	"ArrayLengthMin":    attributeKeywords("min", "minItems"),
+	"StringInOptions":   attributeKeywords("options", "enum"),
+	"StringsExistsInOptions": func(attributes map[string]interface{}) map[string]interface{} {
+		if options, ok := attributes["options"]; ok {
+			return map[string]interface{}{"type": "array", "items": map[string]interface{}{"enum": options}}
+		}
+		return nil
+	},
+}
+
+// jsonSchemaTypes are the JSON Schema types of the field types
+var jsonSchemaTypes = map[string]map[string]interface{}{
+	"string":  {"type": "string"},
+	"number":  {"type": "number"},
+	"integer": {"type": "integer"},
+	"boolean": {"type": "boolean"},
+	"date":    {"type": "string"},
+	"object":  {"type": "object"},
+	"array":   {"type": "array"},
+}
+
+func fixedKeywords(keywords map[string]interface{}) func(map[string]interface{}) map[string]interface{} {
+	return func(map[string]interface{}) map[string]interface{} {
+		return keywords
+	}
+}
+
+// attributeKeywords maps the attributes to keywords, pairs is a list of attribute and keyword, every attribute is required
+func attributeKeywords(pairs ...string) func(map[string]interface{}) map[string]interface{} {
+	return func(attributes map[string]interface{}) map[string]interface{} {
+		keywords := make(map[string]interface{})
+		for i := 0; i+1 < len(pairs); i += 2 {
+			value, exists := attributes[pairs[i]]
+			if !exists {
+				return nil
+			}
+			keywords[pairs[i+1]] = value
+		}
+		return keywords
+	}
+}
+
+// JsonSchema converts the schema to a JSON Schema (draft 2020-12). The targets become nested properties, * becomes the
+// items of an array, and the basic validators become keywords, e.g. MaxLengthAllowed is maxLength and InBetween is
+// minimum and maximum. The validators that can not be mapped, the operators, conditions, when and the dependencies that
+// are not in the same object are kept in x- extensions
+func (s *Schematics) JsonSchema() map[string]interface{} {
+	separator := s.Separator
+	if separator == "" {
+		separator = "."
+	}
+	root := newJsonSchemaNode()
+	root.addFields(s.Schema.Fields, separator)
+	schema := root.render(!s.Schema.allowsAdditionalFields())
+	schema["$schema"] = JsonSchemaDraft
+	schema["type"] = "object"
+	if s.Schema.Version != "" {
+		schema["x-version"] = s.Schema.Version
+	}
+	return schema
+}
+
+// ExportJsonSchema returns the JsonSchema of the schematics as indented JSON
+func (s *Schematics) ExportJsonSchema() ([]byte, error) {
+	return json.MarshalIndent(s.JsonSchema(), "", "  ")
+}
+
+type jsonSchemaNode struct {
+	keywords          map[string]interface{}
+	nullable          bool
+	properties        map[string]*jsonSchemaNode
+	items             *jsonSchemaNode
+	required          []string
+	dependentRequired map[string][]string
+}
+
+func newJsonSchemaNode() *jsonSchemaNode {
+	return &jsonSchemaNode{keywords: map[string]interface{}{}, properties: map[string]*jsonSchemaNode{}, dependentRequired: map[string][]string{}}
+}
+
+// addFields adds the fields to the node, the targets are relative to the node
+func (n *jsonSchemaNode) addFields(fields map[TargetKey]Field, separator string) {
+	for _, target := range sortedTargets(fields) {
+		field := fields[target]
+		parts := strings.Split(string(target), separator)
+		parent := n
+		for _, part := range parts[:len(parts)-1] {
+			if field.IsRequired && part != "*" {
+				parent.require(part)
+			}
+			parent = parent.child(part)
+		}
+		name := parts[len(parts)-1]
+		if field.IsRequired && name != "*" {
+			parent.require(name)
+		}
+
+		var external []string
+		for _, dependency := range field.DependsOn {
+			dependencyParts := strings.Split(dependency, separator)
+			if name != "*" && len(dependencyParts) == len(parts) && strings.Join(dependencyParts[:len(parts)-1], separator) == strings.Join(parts[:len(parts)-1], separator) {
+				parent.dependentRequired[name] = append(parent.dependentRequired[name], dependencyParts[len(parts)-1])
+				continue
+			}
+			external = append(external, dependency)
+		}
+
+		node := parent.child(name)
+		if len(external) > 0 {
+			node.keywords["x-depends-on"] = external
+		}
+		node.addField(field, separator)
+	}
+}
+
+func (n *jsonSchemaNode) addField(field Field, separator string) {
+	if jsonType, ok := jsonSchemaTypes[strings.ToLower(strings.TrimSpace(field.Type))]; ok {
+		n.set(jsonType)
+	}
+	if field.DisplayName != "" {
+		n.keywords["title"] = field.DisplayName
+	} else if field.Name != "" {
+		n.keywords["title"] = field.Name
+	}
+	if field.Description != "" {
+		n.keywords["description"] = field.Description
+	}
+	if field.Default != nil {
+		n.keywords["default"] = field.Default
+	}
+	n.nullable = n.nullable || field.Nullable
+
+	var unmapped []map[string]interface{}
+	for _, component := range field.ValidatorComponents() {
+		if toKeywords, ok := jsonSchemaKeywords[component.Name]; ok {
+			if keywords := toKeywords(component.Attributes); keywords != nil {
+				n.set(keywords)
+				continue
+			}
+		}
+		unmapped = append(unmapped, jsonSchemaComponent(component))
+	}
+	if len(unmapped) > 0 {
+		n.keywords["x-validators"] = unmapped
+	}
+	var operators []map[string]interface{}
+	for _, component := range field.OperatorComponents() {
+		operators = append(operators, jsonSchemaComponent(component))
+	}
+	if len(operators) > 0 {
+		n.keywords["x-operators"] = operators
+	}
+	if len(field.Conditions) > 0 {
+		n.keywords["x-conditions"] = field.Conditions
+	}
+	if strings.TrimSpace(field.When) != "" {
+		n.keywords["x-when"] = field.When
+	}
+
+	if len(field.Fields) > 0 {
+		n.addFields(field.Fields, separator)
+	}
+	if field.Items != nil {
+		n.child("*").addField(*field.Items, separator)
+	}
+}
+
+func jsonSchemaComponent(component Component) map[string]interface{} {
+	exported := map[string]interface{}{"name": component.Name}
+	if len(component.Attributes) > 0 {
+		exported["attributes"] = component.Attributes
+	}
+	if component.Error != "" {
+		exported["error"] = component.Error
+	}
+	return exported
+}
+
+// set adds the keywords, a keyword that is already set with another value is added to allOf so both apply
+func (n *jsonSchemaNode) set(keywords map[string]interface{}) {
+	for _, keyword := range sortedKeywords(keywords) {
+		value := keywords[keyword]
+		if existing, exists := n.keywords[keyword]; exists && !reflect.DeepEqual(existing, value) {
+			allOf, _ := n.keywords["allOf"].([]interface{})
+			n.keywords["allOf"] = append(allOf, map[string]interface{}{keyword: value})
+			continue
+		}
+		n.keywords[keyword] = value
+	}
+}
+
+func (n *jsonSchemaNode) child(name string) *jsonSchemaNode {
+	if name == "*" {
+		if n.items == nil {
+			n.items = newJsonSchemaNode()
+		}
+		return n.items
+	}
+	if _, exists := n.properties[name]; !exists {
+		n.properties[name] = newJsonSchemaNode()
+	}
+	return n.properties[name]
+}
+
+func (n *jsonSchemaNode) require(name string) {
+	for _, required := range n.required {
+		if required == name {
+			return
+		}
+	}
+	n.required = append(n.required, name)
+}
+
+// render returns the JSON Schema of the node, closed objects do not allow properties that are not declared
+func (n *jsonSchemaNode) render(closed bool) map[string]interface{} {
+	schema := make(map[string]interface{}, len(n.keywords))
+	for keyword, value := range n.keywords {
+		schema[keyword] = value
+	}
+	if len(n.properties) > 0 {
+		properties := make(map[string]interface{}, len(n.properties))
+		for name, property := range n.properties {
+			properties[name] = property.render(closed)
+		}
+		schema["properties"] = properties
+		if _, typed := schema["type"]; !typed {
+			schema["type"] = "object"
+		}
+		if closed {
+			schema["additionalProperties"] = false
+		}
+	}
+	if n.items != nil {
+		if items, exists := schema["items"]; exists {
+			allOf, _ := schema["allOf"].([]interface{})
+			schema["allOf"] = append(allOf, map[string]interface{}{"items": items})
+		}
+		schema["items"] = n.items.render(closed)
+		if _, typed := schema["type"]; !typed {
+			schema["type"] = "array"
+		}
+	}
+	if len(n.required) > 0 {
+		schema["required"] = n.required
+	}
+	if len(n.dependentRequired) > 0 {
+		schema["dependentRequired"] = n.dependentRequired
+	}
+	if jsonType, ok := schema["type"].(string); ok && n.nullable {
+		schema["type"] = []string{jsonType, "null"}
+	}
+	return schema
+}
+
+func sortedKeywords(keywords map[string]interface{}) []string {
+	names := make([]string, 0, len(keywords))
+	for name := range keywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,183 @@
+package v0
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExportJsonSchema(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"strict":  true,
+		"fields": map[string]interface{}{
+			"user.name": map[string]interface{}{
+				"type":         "string",
+				"display_name": "Name",
+				"required":     true,
+				"validators": []interface{}{
+					map[string]interface{}{"name": "MinLengthAllowed", "attributes": map[string]interface{}{"min": 2}},
+					map[string]interface{}{"name": "MaxLengthAllowed", "attributes": map[string]interface{}{"max": 20}},
+				},
+				"operators": []interface{}{map[string]interface{}{"name": "Trim"}},
+			},
+			"user.age": map[string]interface{}{
+				"depends_on": []interface{}{"user.name"},
+				"nullable":   true,
+				"validators": []interface{}{
+					map[string]interface{}{"name": "IsInteger"},
+					map[string]interface{}{"name": "InBetween", "attributes": map[string]interface{}{"min": 18, "max": 99}},
+				},
+			},
+			"user.birthday": map[string]interface{}{
+				"type":       "date",
+				"validators": []interface{}{map[string]interface{}{"name": "IsValidDate"}},
+			},
+			"user.email": map[string]interface{}{
+				"validators": []interface{}{
+					map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "@"}},
+					map[string]interface{}{"name": "IsCompanyEmail", "attributes": map[string]interface{}{"domain": "example.com"}},
+				},
+			},
+			"tags.*": map[string]interface{}{
+				"validators": []interface{}{map[string]interface{}{"name": "StringInOptions", "attributes": map[string]interface{}{"options": []interface{}{"a", "b"}}}},
+			},
+		},
+	})
+	exported, err := json.Marshal(schematics.JsonSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","additionalProperties":false,"properties":{` +
+		`"tags":{"items":{"enum":["a","b"]},"type":"array"},` +
+		`"user":{"additionalProperties":false,"dependentRequired":{"age":["name"]},"properties":{` +
+		`"age":{"maximum":99,"minimum":18,"type":["integer","null"]},` +
+		`"birthday":{"type":"string"},` +
+		`"email":{"pattern":"@","x-validators":[{"attributes":{"domain":"example.com"},"name":"IsCompanyEmail"}]},` +
+		`"name":{"maxLength":20,"minLength":2,"title":"Name","type":"string","x-operators":[{"name":"Trim"}]}},` +
+		`"required":["name"],"type":"object"}},"required":["user"],"type":"object","x-version":"0"}`
+	if string(exported) != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, exported)
+	}
+}
+
+func TestImportJsonSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		contract   string
+		report     []string
+		collectAll bool
+		cases      []validationCase
+	}{
+		{
+			name: "keywords",
+			contract: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"required": ["user"],
+				"additionalProperties": false,
+				"$defs": {"name": {"type": "string", "minLength": 2, "maxLength": 20}},
+				"properties": {
+					"user": {
+						"type": "object",
+						"required": ["name", "email"],
+						"dependentRequired": {"age": ["name"]},
+						"properties": {
+							"name": {"$ref": "#/$defs/name"},
+							"email": {"type": "string", "format": "email"},
+							"age": {"type": ["integer", "null"], "minimum": 18, "exclusiveMaximum": 130}
+						}
+					},
+					"tags": {"type": "array", "maxItems": 3, "items": {"enum": ["a", "b"]}},
+					"code": {"oneOf": [{"pattern": "^A"}, {"pattern": "^B"}]}
+				}
+			}`,
+			report: []string{
+				"warning: #/properties/code/oneOf: oneOf is not translated",
+				"warning: #/properties/user/properties/age/exclusiveMaximum: exclusiveMaximum is not translated",
+			},
+			cases: []validationCase{
+				{"invalid", map[string]interface{}{
+					"user": map[string]interface{}{"name": "J", "email": "not-an-email", "age": 12},
+					"tags": []interface{}{"a", "c"},
+				}, []string{"tags.1", "user.name", "user.age", "user.email"}},
+				{"valid", map[string]interface{}{
+					"user": map[string]interface{}{"name": "John", "email": "john@example.com", "age": nil},
+					"tags": []interface{}{"a"},
+				}, nil},
+			},
+		},
+		{
+			name: "nested",
+			contract: `{
+				"type": "object",
+				"$defs": {
+					"node": {
+						"type": "object",
+						"required": ["name"],
+						"properties": {
+							"name": {"type": "string"},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+						}
+					}
+				},
+				"properties": {
+					"address": {"type": "object", "required": ["city"], "properties": {"city": {"type": "string"}}},
+					"orders": {"type": "array", "items": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}},
+					"tree": {"$ref": "#/$defs/node"}
+				}
+			}`,
+			report:     []string{"warning: #/properties/tree/properties/children/items/$ref: the recursive reference #/$defs/node is not translated"},
+			collectAll: true,
+			// the required properties are only required when their object is provided
+			cases: []validationCase{
+				{"nothing provided", map[string]interface{}{}, nil},
+				{"empty array", map[string]interface{}{"orders": []interface{}{}}, nil},
+				{"valid object", map[string]interface{}{"address": map[string]interface{}{"city": "Dubai"}}, nil},
+				{"missing nested", map[string]interface{}{
+					"address": map[string]interface{}{},
+					"orders":  []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{}},
+					"tree":    map[string]interface{}{"name": "root", "children": []interface{}{map[string]interface{}{"name": 1}}},
+				}, []string{"address.city", "orders.1.id"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		var schematics Schematics
+		report, err := schematics.ImportJsonSchema([]byte(test.contract))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, diagnostic := range report {
+			got = append(got, diagnostic.String())
+		}
+		if strings.Join(got, "\n") != strings.Join(test.report, "\n") {
+			t.Fatalf("%s: expected the report\n%s\ngot\n%s", test.name, strings.Join(test.report, "\n"), strings.Join(got, "\n"))
+		}
+		schematics.Schema.CollectAllErrors = test.collectAll
+		runValidationCases(t, &schematics, test.cases)
+	}
+}
+
+func TestImportJsonSchemaFields(t *testing.T) {
+	var schematics Schematics
+	_, err := schematics.ImportJsonSchema([]byte(`{
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {"user": {"type": "object", "dependentRequired": {"age": ["name"]}, "properties": {
+			"name": {"type": "string"},
+			"age": {"type": ["integer", "null"], "minimum": 18}
+		}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schematics.Schema.Strict {
+		t.Fatal("expected the schema to be strict")
+	}
+	age := schematics.Schema.Fields["user"].Fields["age"]
+	if age.Type != "integer" || !age.Nullable || age.DependsOn[0] != "name" || age.Validators["MinAllowed"].Attributes["min"] != float64(18) {
+		t.Fatalf("unexpected user.age field %+v", age)
+	}
+}
@@ -0,0 +1,324 @@
+package v0
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/conditions"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"github.com/ashbeelghouri/jsonschematics/validators"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type Severity string
+
+const (
+	// SeverityError is a problem that makes the field fail or be skipped at validation time
+	SeverityError Severity = "error"
+	// SeverityWarning is something that is allowed but most likely not intended
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in the schema, Path is where it is in the schema, e.g. fields[user.email].validators[LIKE].attributes.pattern
+type Diagnostic struct {
+	Path     string
+	Severity Severity
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Path, d.Message)
+}
+
+// requiredAttributes are the attributes the basic validators and conditions can not run without
+var requiredAttributes = map[string][]string{
+	"LIKE":                   {"pattern"},
+	"MatchRegex":             {"regex"},
+	"MaxLengthAllowed":       {"max"},
+	"MinLengthAllowed":       {"min"},
+	"InBetweenLengthAllowed": {"min", "max"},
+	"MaxAllowed":             {"max"},
+	"MinAllowed":             {"min"},
+	"InBetween":              {"min", "max"},
+	"IsBefore":               {"maxTime"},
+	"IsAfter":                {"maxTime"},
+	"IsInBetweenTime":        {"minTime", "maxTime"},
+	"ArrayLengthMax":         {"max"},
+	"ArrayLengthMin":         {"min"},
+	"StringInOptions":        {"options"},
+	"StringsExistsInOptions": {"options"},
+	"HaveURLHostName":        {"host"},
+	"HaveQueryParameter":     {"params"},
+	"EqualsField":            {"field"},
+	"NotEqualsField":         {"field"},
+	"GreaterThanField":       {"field"},
+	"DateAfterField":         {"field"},
+}
+
+var requiredConditionAttributes = map[string][]string{
+	"FieldIsProvided":   {"shouldBeProvided"},
+	"Expression":        {"expression"},
+	"FieldEquals":       {"field", "value"},
+	"FieldIn":           {"field", "values"},
+	"FieldMatchesRegex": {"field", "regex"},
+	"FieldGreaterThan":  {"field", "value"},
+	"FieldIsEmpty":      {"field"},
+	"FieldIsMissing":    {"field"},
+	"AllOf":             {"conditions"},
+	"AnyOf":             {"conditions"},
+	"Not":               {"conditions"},
+}
+
+// regexAttributes and dateAttributes are the attributes that are checked to be a valid regex or date
+var regexAttributes = []string{"regex"}
+var dateAttributes = []string{"minTime", "maxTime"}
+
+// Lint checks the schema against the registered validators, operators and conditions and returns what it finds,
+// in the order of the targets. Nothing is changed and the schema can still be used when there are errors
+func (s *Schematics) Lint() []Diagnostic {
+	l := linter{schematics: s}
+	l.fields(s.Schema.Fields, "")
+	return l.diagnostics
+}
+
+// Check returns an error listing the diagnostics of Lint with the error severity
+func (s *Schematics) Check() error {
+	var messages []string
+	for _, diagnostic := range s.Lint() {
+		if diagnostic.Severity == SeverityError {
+			messages = append(messages, fmt.Sprintf("%s: %s", diagnostic.Path, diagnostic.Message))
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New("the schema has errors: " + strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+// CheckExpressions returns an error listing the when of the fields, and the expression of the Expression conditions, that
+// can not be parsed. The loaders check them even without FailOnLintErrors, an invalid when is skipped at validation time
+// and its field always validated
+func (s Schema) CheckExpressions() error {
+	var messages []string
+	checkExpressions(s.Fields, "", &messages)
+	if len(messages) > 0 {
+		return errors.New("the schema has invalid expressions: " + strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+func checkExpressions(fields map[TargetKey]Field, parent string, messages *[]string) {
+	for _, target := range sortedTargets(fields) {
+		checkFieldExpressions(fields[target], fmt.Sprintf("%sfields[%s]", pathPrefix(parent), target), messages)
+	}
+}
+
+func checkFieldExpressions(field Field, path string, messages *[]string) {
+	if strings.TrimSpace(field.When) != "" {
+		if _, err := conditions.ParseExpression(field.When); err != nil {
+			*messages = append(*messages, fmt.Sprintf("%s.when: %v", path, err))
+		}
+	}
+	conditionNames := make([]string, 0, len(field.Conditions))
+	for name := range field.Conditions {
+		conditionNames = append(conditionNames, name)
+	}
+	sort.Strings(conditionNames)
+	for _, name := range conditionNames {
+		checkConditionExpressions(fmt.Sprintf("%s.conditions[%s]", path, name), name, field.Conditions[name].Attributes, messages)
+	}
+	checkExpressions(field.Fields, path, messages)
+	if field.Items != nil {
+		checkFieldExpressions(*field.Items, path+".items", messages)
+	}
+}
+
+// checkConditionExpressions checks the expression of an Expression condition and of the Expression conditions nested in the combinators
+func checkConditionExpressions(path string, name string, attributes map[string]interface{}, messages *[]string) {
+	switch name {
+	case "Expression":
+		if source, ok := attributes["expression"].(string); ok {
+			if _, err := conditions.ParseExpression(source); err != nil {
+				*messages = append(*messages, fmt.Sprintf("%s.attributes.expression: %v", path, err))
+			}
+		}
+	case "AllOf", "AnyOf", "Not":
+		for i, nested := range conditions.NestedConditions(attributes) {
+			checkConditionExpressions(fmt.Sprintf("%s.attributes.conditions[%d]", path, i), nested.Name, nested.Attributes, messages)
+		}
+	}
+}
+
+type linter struct {
+	schematics  *Schematics
+	diagnostics []Diagnostic
+}
+
+func (l *linter) add(path string, severity Severity, format string, args ...interface{}) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) fields(fields map[TargetKey]Field, parent string) {
+	if _, err := DependencyOrder(fields); err != nil {
+		path := "fields"
+		if parent != "" {
+			path = parent + ".fields"
+		}
+		l.add(path, SeverityError, "%v", err)
+	}
+	for _, target := range sortedTargets(fields) {
+		l.field(fields, target, fmt.Sprintf("%sfields[%s]", pathPrefix(parent), target))
+	}
+}
+
+func (l *linter) field(fields map[TargetKey]Field, target TargetKey, path string) {
+	field := fields[target]
+	if _, err := regexp.Compile(utils.ConvertKeyToRegex(string(target))); err != nil {
+		l.add(path, SeverityError, "target is not a valid pattern: %v", err)
+	}
+	if field.Type != "" && !knownFieldType(strings.ToLower(strings.TrimSpace(field.Type))) {
+		l.add(path+".type", SeverityError, "unknown type %s, the type should be one of %s", field.Type, strings.Join(FieldTypes, ", "))
+	}
+	if strings.TrimSpace(field.When) != "" {
+		if _, err := conditions.ParseExpression(field.When); err != nil {
+			l.add(path+".when", SeverityError, "%v", err)
+		}
+	}
+	for i, dependency := range field.DependsOn {
+		if _, isField := fields[TargetKey(dependency)]; !isField {
+			l.add(fmt.Sprintf("%s.depends_on[%d]", path, i), SeverityWarning, "%s is not a field of the schema, it is only checked to be provided", dependency)
+		}
+	}
+
+	validatorComponents := field.ValidatorComponents()
+	for i, component := range validatorComponents {
+		componentPath := fmt.Sprintf("%s.validators[%s]", path, componentLabel(validatorComponents, i))
+		if component.Name == "" {
+			l.add(componentPath, SeverityError, "validator name is required")
+			continue
+		}
+		if utils.StringInStrings(strings.ToUpper(component.Name), utils.ExcludedValidators) {
+			continue
+		}
+		if _, ok := l.schematics.Validators.GetDocument(component.Name); !ok {
+			l.add(componentPath, SeverityError, "validator %s is not registered", component.Name)
+			continue
+		}
+		l.attributes(componentPath, component.Name, component.Attributes, requiredAttributes)
+	}
+
+	operatorComponents := field.OperatorComponents()
+	for i, component := range operatorComponents {
+		if _, ok := l.schematics.Operators.OpFunctions[component.Name]; !ok {
+			l.add(fmt.Sprintf("%s.operators[%s]", path, componentLabel(operatorComponents, i)), SeverityError, "operator %s is not registered", component.Name)
+		}
+	}
+
+	conditionNames := make([]string, 0, len(field.Conditions))
+	for name := range field.Conditions {
+		conditionNames = append(conditionNames, name)
+	}
+	sort.Strings(conditionNames)
+	for _, name := range conditionNames {
+		condition := field.Conditions[name]
+		conditionPath := fmt.Sprintf("%s.conditions[%s]", path, name)
+		l.condition(conditionPath, name, condition.Attributes)
+		if condition.Action != nil {
+			l.actions(conditionPath, field, condition.Action)
+		}
+	}
+
+	if len(field.Fields) > 0 {
+		l.fields(field.Fields, path)
+	}
+	if field.Items != nil {
+		items := map[TargetKey]Field{"*": *field.Items}
+		l.field(items, "*", path+".items")
+	}
+}
+
+// condition checks the condition and the conditions nested in the combinators
+func (l *linter) condition(path string, name string, attributes map[string]interface{}) {
+	if _, ok := l.schematics.Conditions.ConditionFns[name]; !ok {
+		l.add(path, SeverityError, "condition %s is not registered", name)
+		return
+	}
+	l.attributes(path, name, attributes, requiredConditionAttributes)
+	if name == "Expression" {
+		if source, ok := attributes["expression"].(string); ok {
+			if _, err := conditions.ParseExpression(source); err != nil {
+				l.add(path+".attributes.expression", SeverityError, "%v", err)
+			}
+		}
+	}
+	if name == "AllOf" || name == "AnyOf" || name == "Not" {
+		for i, nested := range conditions.NestedConditions(attributes) {
+			l.condition(fmt.Sprintf("%s.attributes.conditions[%d]", path, i), nested.Name, nested.Attributes)
+		}
+	}
+}
+
+func (l *linter) actions(path string, field Field, action *ConditionalAction) {
+	check := func(kind string, actions []string) {
+		for i, a := range actions {
+			actionPath := fmt.Sprintf("%s.action.%s[%d]", path, kind, i)
+			actionKind, name, err := parseAction(a)
+			if err != nil {
+				l.add(actionPath, SeverityError, "%v", err)
+				continue
+			}
+			switch actionKind {
+			case "enable", "disable":
+				if _, exists := field.validatorsByName()[name]; !exists {
+					l.add(actionPath, SeverityError, "validator %s is not on the field", name)
+				}
+			case "operate":
+				if _, ok := l.schematics.Operators.OpFunctions[name]; !ok {
+					l.add(actionPath, SeverityError, "operator %s is not registered", name)
+				}
+			}
+		}
+	}
+	check("success", action.Success)
+	check("error", action.Error)
+}
+
+// attributes checks the required attributes of the validator or condition and the attributes that should be a regex or a date
+func (l *linter) attributes(path string, name string, attributes map[string]interface{}, required map[string][]string) {
+	for _, attribute := range required[name] {
+		if _, exists := attributes[attribute]; !exists {
+			l.add(path+".attributes", SeverityError, "%s attribute is required by %s", attribute, name)
+		}
+	}
+	if _, known := required[name]; !known {
+		return
+	}
+	for _, attribute := range regexAttributes {
+		if pattern, ok := attributes[attribute].(string); ok {
+			if _, err := regexp.Compile(pattern); err != nil {
+				l.add(path+".attributes."+attribute, SeverityError, "not a valid regex: %v", err)
+			}
+		}
+	}
+	for _, attribute := range dateAttributes {
+		if value, exists := attributes[attribute]; exists && validators.InterfaceToDate(value) == nil {
+			l.add(path+".attributes."+attribute, SeverityError, "%v is not a valid date", value)
+		}
+	}
+}
+
+func (f Field) validatorsByName() map[string]bool {
+	names := make(map[string]bool)
+	for _, component := range f.ValidatorComponents() {
+		names[component.Name] = true
+	}
+	return names
+}
+
+func pathPrefix(parent string) string {
+	if parent == "" {
+		return ""
+	}
+	return parent + "."
+}
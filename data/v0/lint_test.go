@@ -0,0 +1,53 @@
+package v0
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintSchema(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"user.name": map[string]interface{}{
+				"type":       "text",
+				"depends_on": []interface{}{"user.id"},
+				"validators": []interface{}{
+					map[string]interface{}{"name": "IsGreen"},
+					map[string]interface{}{"name": "LIKE"},
+					map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "("}},
+				},
+				"operators": []interface{}{map[string]interface{}{"name": "Shout"}},
+			},
+			"orders": map[string]interface{}{
+				"items": map[string]interface{}{
+					"fields": map[string]interface{}{
+						"date": map[string]interface{}{
+							"validators": map[string]interface{}{"IsBefore": map[string]interface{}{"attributes": map[string]interface{}{"maxTime": "tomorrow"}}},
+							"conditions": map[string]interface{}{"AnyOf": map[string]interface{}{"attributes": map[string]interface{}{"conditions": []interface{}{
+								map[string]interface{}{"name": "FieldEquals", "attributes": map[string]interface{}{"field": "type"}},
+							}}}},
+						},
+					},
+				},
+			},
+		},
+	})
+	var got []string
+	for _, diagnostic := range schematics.Lint() {
+		got = append(got, diagnostic.String())
+	}
+	expected := []string{
+		"error: fields[orders].items.fields[date].validators[IsBefore].attributes.maxTime: tomorrow is not a valid date",
+		"error: fields[orders].items.fields[date].conditions[AnyOf].attributes.conditions[0].attributes: value attribute is required by FieldEquals",
+		"error: fields[user.name].type: unknown type text, the type should be one of string, number, integer, boolean, date, object, array",
+		"warning: fields[user.name].depends_on[0]: user.id is not a field of the schema, it is only checked to be provided",
+		"error: fields[user.name].validators[IsGreen]: validator IsGreen is not registered",
+		"error: fields[user.name].validators[LIKE].attributes: pattern attribute is required by LIKE",
+		"error: fields[user.name].validators[MatchRegex].attributes.regex: not a valid regex: error parsing regexp: missing closing ): `(`",
+		"error: fields[user.name].operators[Shout]: operator Shout is not registered",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected the diagnostics\n%s\ngot\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
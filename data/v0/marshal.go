@@ -0,0 +1,132 @@
+package v0
+
+import (
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"os"
+	"sort"
+)
+
+// Marshal returns the schema in the v0 file format as indented JSON. The fields, validators and operators are written
+// in the order they were declared in and a pipeline is written as a list of components, so loading the result gives
+// the same schema. Only the keys that are set are written, a schema loaded with extends or $ref is not marshaled, see
+// CheckStandalone
+func (s *Schematics) Marshal() ([]byte, error) {
+	if err := s.Schema.CheckStandalone(); err != nil {
+		s.Logging.ERROR("The schema can not be written", err)
+		return nil, err
+	}
+	content, err := utils.MarshalIndent(s.Schema.fileObject())
+	if err != nil {
+		s.Logging.ERROR("Failed to marshal the schema", err)
+		return nil, err
+	}
+	return content, nil
+}
+
+// Save writes the schema to the file in the v0 file format, see Marshal
+func (s *Schematics) Save(path string) error {
+	content, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		s.Logging.ERROR("Failed to save the schema file", err)
+		return err
+	}
+	return nil
+}
+
+// OrderedTargets returns the targets of the fields in the order, the targets missing from it come after the others
+// sorted by target
+func OrderedTargets(fields map[TargetKey]Field, order []TargetKey) []TargetKey {
+	targets := make([]TargetKey, 0, len(fields))
+	seen := make(map[TargetKey]bool, len(fields))
+	for _, target := range order {
+		if _, exists := fields[target]; exists && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	for _, target := range sortedTargets(fields) {
+		if !seen[target] {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+func (s Schema) fileObject() *utils.OrderedObject {
+	file := utils.CompactJson(s).(*utils.OrderedObject)
+	if s.Fields != nil {
+		file.Set("fields", fileFields(s.Fields, s.FieldsOrder))
+	}
+	if s.Definitions != nil {
+		names := make([]string, 0, len(s.Definitions))
+		for name := range s.Definitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		definitions := utils.NewOrderedObject()
+		for _, name := range names {
+			definitions.Set(name, fileField(s.Definitions[name]))
+		}
+		file.Set("definitions", definitions)
+	}
+	return file
+}
+
+func fileFields(fields map[TargetKey]Field, order []TargetKey) *utils.OrderedObject {
+	file := utils.NewOrderedObject()
+	for _, target := range OrderedTargets(fields, order) {
+		file.Set(string(target), fileField(fields[target]))
+	}
+	return file
+}
+
+// fileField is the field as it is written in the file, the state of the validation is left out
+func fileField(field Field) *utils.OrderedObject {
+	file := utils.CompactJson(field).(*utils.OrderedObject)
+	for _, key := range []string{"value", "Provided", "Status", "Errors"} {
+		file.Delete(key)
+	}
+	if field.Validators != nil || len(field.ValidatorPipeline) > 0 {
+		file.Set("validators", fileComponents(field.ValidatorComponents(), len(field.ValidatorPipeline) > 0))
+	}
+	if field.Operators != nil || len(field.OperatorPipeline) > 0 {
+		file.Set("operators", fileComponents(field.OperatorComponents(), len(field.OperatorPipeline) > 0))
+	}
+	if field.Fields != nil {
+		file.Set("fields", fileFields(field.Fields, field.FieldsOrder))
+	}
+	if field.Items != nil {
+		file.Set("items", fileField(*field.Items))
+	}
+	return file
+}
+
+// fileComponents writes the components as a list when they are a pipeline, otherwise as a map in their order
+func fileComponents(components []Component, pipeline bool) interface{} {
+	if pipeline {
+		list := make([]interface{}, len(components))
+		for i, component := range components {
+			list[i] = utils.CompactJson(component)
+		}
+		return list
+	}
+	file := utils.NewOrderedObject()
+	for _, component := range components {
+		file.Set(component.Name, utils.CompactJson(component.Constant))
+	}
+	return file
+}
+
+func targetKeys(names []string) []TargetKey {
+	if names == nil {
+		return nil
+	}
+	targets := make([]TargetKey, len(names))
+	for i, name := range names {
+		targets[i] = TargetKey(name)
+	}
+	return targets
+}
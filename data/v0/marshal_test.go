@@ -0,0 +1,96 @@
+package v0
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeTemp(t *testing.T, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func componentNames(components []Component) string {
+	names := make([]string, len(components))
+	for i, component := range components {
+		names[i] = component.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestMarshalSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files, _ := filepath.Glob("../../test-data/schema/direct/v0/*.json")
+	for _, file := range files {
+		var loaded Schematics
+		if err := loaded.LoadJsonSchemaFile(file); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		saved := filepath.Join(dir, filepath.Base(file))
+		if err := loaded.Save(saved); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		var reloaded Schematics
+		if err := reloaded.LoadJsonSchemaFile(saved); err != nil {
+			t.Fatalf("%s: %v", saved, err)
+		}
+		if !reflect.DeepEqual(loaded.Schema, reloaded.Schema) {
+			t.Fatalf("%s changed after saving it:\n%+v\n%+v", file, loaded.Schema, reloaded.Schema)
+		}
+		resaved := saved + ".again"
+		if err := reloaded.Save(resaved); err != nil {
+			t.Fatal(err)
+		}
+		first, _ := os.ReadFile(saved)
+		second, _ := os.ReadFile(resaved)
+		if string(first) != string(second) {
+			t.Fatalf("%s is not saved the same way twice:\n%s\n%s", file, first, second)
+		}
+	}
+}
+
+func TestMarshalKeepsTheDeclaredOrder(t *testing.T) {
+	var schematics Schematics
+	err := schematics.LoadJsonSchemaFile(writeTemp(t, []byte(`{
+		"version": "0",
+		"fields": {
+			"name": {"type": "string", "validators": [
+				{"name": "MatchRegex", "attributes": {"regex": "^a"}},
+				{"name": "IsString"},
+				{"name": "MatchRegex", "attributes": {"regex": "z$"}}
+			]},
+			"age": {"type": "number"}
+		}
+	}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := schematics.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reloaded Schematics
+	if err := reloaded.LoadJsonSchemaFile(writeTemp(t, content)); err != nil {
+		t.Fatal(err)
+	}
+	name := reloaded.Schema.Fields["name"]
+	if names := componentNames(name.ValidatorComponents()); names != "MatchRegex,IsString,MatchRegex" {
+		t.Fatalf("expected the pipeline to be kept, got %s", names)
+	}
+	if order := fmt.Sprint(reloaded.Schema.FieldsOrder); order != "[name age]" {
+		t.Fatalf("expected the order of the fields to be kept, got %s", order)
+	}
+
+	schematics.Schema.Resolved = []string{"extends base.json"}
+	if _, err := schematics.Marshal(); err == nil {
+		t.Fatal("expected the schema loaded with extends not to be marshaled")
+	}
+}
@@ -0,0 +1,274 @@
+package v0
+
+import (
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"reflect"
+)
+
+// The merge strategies of a field in the overlay schema
+const (
+	// MergeReplace replaces the field of the base schema, it is used when the field has no strategy
+	MergeReplace = "replace"
+	// MergeAppend keeps the field of the base schema and adds the validators and operators of the overlay to it,
+	// the ones with the same name as in the base schema take the place of the base ones
+	MergeAppend = "append"
+	// MergeRemove removes the field of the base schema
+	MergeRemove = "remove"
+)
+
+// MergeConflict is a definition in the overlay schema that conflicts with the base schema, the overlay always wins
+type MergeConflict struct {
+	Target  string
+	Message string
+}
+
+func (c MergeConflict) String() string {
+	return fmt.Sprintf("%s: %s", c.Target, c.Message)
+}
+
+// Merge merges the overlay schema into the schema of the schematics and returns the conflicts
+func (s *Schematics) Merge(overlay *Schematics) []MergeConflict {
+	merged, conflicts := MergeSchemas(s.Schema, overlay.Schema)
+	s.Schema = merged
+	s.DB = utils.CombineTwoMaps(s.DB, overlay.DB)
+	return conflicts
+}
+
+// MergeSchemas merges the overlay on the base schema with the merge strategy of every field of the overlay.
+// Fields defined in both schemas without a strategy, validators with different attributes and validators or
+// targets to remove that do not exist are reported as conflicts, neither of the schemas is changed
+func MergeSchemas(base Schema, overlay Schema) (Schema, []MergeConflict) {
+	var conflicts []MergeConflict
+	merged := base
+	merged.Extends = ""
+	merged.Resolved = append(append([]string(nil), overlay.Resolved...), base.Resolved...)
+	if overlay.Version != "" {
+		merged.Version = overlay.Version
+	}
+	merged.CollectAllErrors = base.CollectAllErrors || overlay.CollectAllErrors
+	merged.Strict = base.Strict || overlay.Strict
+	if overlay.AdditionalFields != nil {
+		merged.AdditionalFields = overlay.AdditionalFields
+	}
+
+	merged.DB = utils.CombineTwoMaps(nil, base.DB)
+	for _, key := range sortedKeys(overlay.DB) {
+		if value, exists := merged.DB[key]; exists && !reflect.DeepEqual(value, overlay.DB[key]) {
+			conflicts = append(conflicts, MergeConflict{Target: "DB." + key, Message: "defined in both schemas with different values"})
+		}
+		merged.DB[key] = overlay.DB[key]
+	}
+
+	if base.Definitions != nil || overlay.Definitions != nil {
+		merged.Definitions = make(map[string]Field, len(base.Definitions)+len(overlay.Definitions))
+		for name, definition := range base.Definitions {
+			merged.Definitions[name] = definition.clone()
+		}
+		for name, definition := range overlay.Definitions {
+			if _, exists := merged.Definitions[name]; exists {
+				conflicts = append(conflicts, MergeConflict{Target: "definitions." + name, Message: "defined in both schemas, the overlay replaces the base"})
+			}
+			merged.Definitions[name] = definition.clone()
+		}
+	}
+
+	var fieldConflicts []MergeConflict
+	merged.Fields, fieldConflicts = mergeFields(base.Fields, overlay.Fields, "")
+	merged.FieldsOrder = appendTargets(merged.Fields, base.FieldsOrder, overlay.FieldsOrder)
+	return merged, append(conflicts, fieldConflicts...)
+}
+
+// mergeFields merges the fields of the overlay on the base fields, prefix is the target of the parent of nested fields
+func mergeFields(base map[TargetKey]Field, overlay map[TargetKey]Field, prefix string) (map[TargetKey]Field, []MergeConflict) {
+	var conflicts []MergeConflict
+	merged := make(map[TargetKey]Field, len(base)+len(overlay))
+	for target, field := range base {
+		merged[target] = field.withoutMergeStrategy()
+	}
+
+	for _, target := range sortedTargets(overlay) {
+		field := overlay[target]
+		name := string(target)
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		baseField, exists := merged[target]
+
+		switch field.Merge {
+		case MergeRemove:
+			if !exists {
+				conflicts = append(conflicts, MergeConflict{Target: name, Message: "field to remove is not defined in the base schema"})
+			}
+			delete(merged, target)
+		case MergeAppend:
+			if !exists {
+				conflicts = append(conflicts, MergeConflict{Target: name, Message: "field to append to is not defined in the base schema, it is added as it is"})
+				merged[target] = field.withoutMergeStrategy()
+				continue
+			}
+			var appendConflicts []MergeConflict
+			merged[target], appendConflicts = appendField(baseField, field, name)
+			conflicts = append(conflicts, appendConflicts...)
+		case "", MergeReplace:
+			if exists && field.Merge == "" {
+				conflicts = append(conflicts, MergeConflict{Target: name, Message: "defined in both schemas, the overlay replaces the base"})
+			}
+			if len(field.RemoveValidators) > 0 || len(field.RemoveOperators) > 0 {
+				conflicts = append(conflicts, MergeConflict{Target: name, Message: "validators and operators are only removed when the field is appended"})
+			}
+			merged[target] = field.withoutMergeStrategy()
+		default:
+			conflicts = append(conflicts, MergeConflict{Target: name, Message: fmt.Sprintf("unknown merge strategy %s, the overlay replaces the base", field.Merge)})
+			merged[target] = field.withoutMergeStrategy()
+		}
+	}
+	return merged, conflicts
+}
+
+// appendField adds the overlay to the base field, the values set on the overlay take the place of the base values
+func appendField(base Field, overlay Field, name string) (Field, []MergeConflict) {
+	var conflicts []MergeConflict
+	merged := base.clone()
+
+	validators, validatorConflicts := appendComponents(merged.ValidatorComponents(), overlay.ValidatorComponents(), overlay.RemoveValidators, name, "validator")
+	operators, operatorConflicts := appendComponents(merged.OperatorComponents(), overlay.OperatorComponents(), overlay.RemoveOperators, name, "operator")
+	conflicts = append(append(conflicts, validatorConflicts...), operatorConflicts...)
+	merged.Validators, merged.ValidatorPipeline = componentsToField(validators)
+	merged.Operators, merged.OperatorPipeline = componentsToField(operators)
+	merged.ValidatorsOrder, merged.OperatorsOrder = nil, nil
+
+	if overlay.DisplayName != "" {
+		merged.DisplayName = overlay.DisplayName
+	}
+	if overlay.Name != "" {
+		merged.Name = overlay.Name
+	}
+	if overlay.Type != "" {
+		merged.Type = overlay.Type
+	}
+	if overlay.Description != "" {
+		merged.Description = overlay.Description
+	}
+	if overlay.When != "" {
+		merged.When = overlay.When
+	}
+	if overlay.Default != nil {
+		merged.Default = overlay.Default
+	}
+	if overlay.CollectAllErrors != nil {
+		collectAll := *overlay.CollectAllErrors
+		merged.CollectAllErrors = &collectAll
+	}
+	if overlay.Items != nil {
+		items := overlay.Items.clone()
+		merged.Items = &items
+	}
+	merged.IsRequired = merged.IsRequired || overlay.IsRequired
+	merged.AddToDB = merged.AddToDB || overlay.AddToDB
+	merged.Nullable = merged.Nullable || overlay.Nullable
+	merged.AllowEmpty = merged.AllowEmpty || overlay.AllowEmpty
+	merged.Coerce = merged.Coerce || overlay.Coerce
+	merged.DependsOn = appendUnique(merged.DependsOn, overlay.DependsOn)
+	merged.Tags = appendUnique(merged.Tags, overlay.Tags)
+	merged.L10n = utils.CombineTwoMaps(utils.CombineTwoMaps(nil, merged.L10n), overlay.L10n)
+	merged.AdditionalInformation = utils.CombineTwoMaps(utils.CombineTwoMaps(nil, merged.AdditionalInformation), overlay.AdditionalInformation)
+	for conditionName, condition := range overlay.Conditions {
+		if merged.Conditions == nil {
+			merged.Conditions = make(map[string]Condition)
+		}
+		merged.Conditions[conditionName] = condition
+	}
+	if len(overlay.Fields) > 0 {
+		var nestedConflicts []MergeConflict
+		merged.Fields, nestedConflicts = mergeFields(merged.Fields, overlay.Fields, name)
+		merged.FieldsOrder = appendTargets(merged.Fields, merged.FieldsOrder, overlay.FieldsOrder)
+		conflicts = append(conflicts, nestedConflicts...)
+	}
+	return merged, conflicts
+}
+
+// appendComponents removes the components with the names to remove, replaces the base components that have the same
+// name as an overlay component and adds the other overlay components at the end
+func appendComponents(base []Component, overlay []Component, remove []string, name string, kind string) ([]Component, []MergeConflict) {
+	var conflicts []MergeConflict
+	removed := make(map[string]bool, len(remove))
+	for _, componentName := range remove {
+		removed[componentName] = true
+		found := false
+		for _, component := range base {
+			found = found || component.Name == componentName
+		}
+		if !found {
+			conflicts = append(conflicts, MergeConflict{Target: name, Message: fmt.Sprintf("%s %s to remove is not defined in the base schema", kind, componentName)})
+		}
+	}
+
+	used := make([]bool, len(overlay))
+	var merged []Component
+	for _, component := range base {
+		if removed[component.Name] {
+			continue
+		}
+		for i, overlayComponent := range overlay {
+			if !used[i] && overlayComponent.Name == component.Name {
+				used[i] = true
+				if !reflect.DeepEqual(component.Constant, overlayComponent.Constant) {
+					conflicts = append(conflicts, MergeConflict{Target: name, Message: fmt.Sprintf("%s %s is defined in both schemas, the overlay replaces the base", kind, component.Name)})
+				}
+				component = overlayComponent
+				break
+			}
+		}
+		merged = append(merged, component)
+	}
+	for i, component := range overlay {
+		if !used[i] {
+			merged = append(merged, component)
+		}
+	}
+	return merged, conflicts
+}
+
+// componentsToField returns the map, the order and the pipeline of the components
+func componentsToField(components []Component) (map[string]Constant, []Component) {
+	constants := make(map[string]Constant, len(components))
+	if len(components) == 0 {
+		return constants, nil
+	}
+	for _, component := range components {
+		constants[component.Name] = component.Constant
+	}
+	return constants, cloneComponents(components)
+}
+
+func appendUnique(values []string, others []string) []string {
+	for _, other := range others {
+		if !utils.StringInStrings(other, values) {
+			values = append(values, other)
+		}
+	}
+	return values
+}
+
+func (f Field) withoutMergeStrategy() Field {
+	f = f.clone()
+	f.Merge = ""
+	f.RemoveValidators = nil
+	f.RemoveOperators = nil
+	return f
+}
+
+// appendTargets is the order of the merged fields, the targets of the base come first and the targets the overlay
+// adds after them
+func appendTargets(fields map[TargetKey]Field, base []TargetKey, overlay []TargetKey) []TargetKey {
+	var targets []TargetKey
+	seen := make(map[TargetKey]bool, len(fields))
+	for _, target := range append(append([]TargetKey(nil), base...), overlay...) {
+		if _, exists := fields[target]; exists && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,105 @@
+package v0
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+)
+
+// Component is a validator or an operator with its name, a list of components runs in its order and can have the same name more than once
+type Component struct {
+	Name string `json:"name"`
+	Constant
+}
+
+// parseComponents reads the validators or operators of a field, they can be declared as a map:
+//
+//	{"Trim": {}, "MatchRegex": {"attributes": {"regex": "^a"}}}
+//
+// or as a list, where the same name can appear more than once:
+//
+//	[{"name": "MatchRegex", "attributes": {"regex": "^a"}}, {"MatchRegex": {"attributes": {"regex": "z$"}}}]
+//
+// the map form returns the declared order of the names, the list form returns the pipeline
+func parseComponents(content json.RawMessage) (map[string]Constant, []string, []Component, error) {
+	if len(content) == 0 || string(content) == "null" {
+		return nil, nil, nil, nil
+	}
+
+	var constants map[string]Constant
+	if err := json.Unmarshal(content, &constants); err == nil {
+		return constants, utils.JsonObjectKeys(content), nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, nil, nil, errors.New("should be a map or a list of components")
+	}
+	constants = make(map[string]Constant)
+	var pipeline []Component
+	for _, item := range items {
+		component, err := parseComponent(item)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		constants[component.Name] = component.Constant
+		pipeline = append(pipeline, component)
+	}
+	return constants, nil, pipeline, nil
+}
+
+func parseComponent(item json.RawMessage) (Component, error) {
+	var component Component
+	var named struct {
+		Name *string `json:"name"`
+	}
+	if err := json.Unmarshal(item, &named); err != nil {
+		return component, errors.New("every component in the list should be an object")
+	}
+	if named.Name != nil {
+		err := json.Unmarshal(item, &component)
+		return component, err
+	}
+
+	var single map[string]Constant
+	if err := json.Unmarshal(item, &single); err != nil || len(single) != 1 {
+		return component, errors.New("every component in the list should have a name")
+	}
+	for name, constant := range single {
+		component = Component{Name: name, Constant: constant}
+	}
+	return component, nil
+}
+
+// ValidatorComponents returns the validators of the field in the order they should run
+func (f *Field) ValidatorComponents() []Component {
+	return components(f.Validators, f.ValidatorsOrder, f.ValidatorPipeline)
+}
+
+// OperatorComponents returns the operators of the field in the order they should run
+func (f *Field) OperatorComponents() []Component {
+	return components(f.Operators, f.OperatorsOrder, f.OperatorPipeline)
+}
+
+func components(constants map[string]Constant, order []string, pipeline []Component) []Component {
+	if len(pipeline) > 0 {
+		return pipeline
+	}
+	var results []Component
+	for _, name := range orderedNames(constants, order) {
+		results = append(results, Component{Name: name, Constant: constants[name]})
+	}
+	return results
+}
+
+func cloneComponents(pipeline []Component) []Component {
+	if pipeline == nil {
+		return nil
+	}
+	pipelineCopy := make([]Component, len(pipeline))
+	for i, component := range pipeline {
+		component.Attributes = utils.CombineTwoMaps(nil, component.Attributes)
+		pipelineCopy[i] = component
+	}
+	return pipelineCopy
+}
@@ -0,0 +1,41 @@
+package v0
+
+import (
+	"testing"
+)
+
+func TestOrderedPipelines(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"code": map[string]interface{}{
+				"validators": []interface{}{
+					map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "^a"}},
+					map[string]interface{}{"MatchRegex": map[string]interface{}{"attributes": map[string]interface{}{"regex": "z$"}}},
+				},
+				"operators": []interface{}{
+					map[string]interface{}{"name": "Trim"},
+					map[string]interface{}{"name": "LowerCase"},
+					map[string]interface{}{"name": "Replace", "attributes": map[string]interface{}{"old": "-", "new": "_"}},
+				},
+			},
+		},
+	})
+	runValidationCases(t, schematics, []validationCase{
+		{"both regex pass", map[string]interface{}{"code": "abz"}, nil},
+		{"second regex fails", map[string]interface{}{"code": "aby"}, []string{"code"}},
+	})
+
+	result, errs := schematics.Operate(map[string]interface{}{"code": "  AB-Z "})
+	if errs.HasErrors() {
+		t.Fatal(errs.Messages)
+	}
+	if (*result.(*map[string]interface{}))["code"] != "ab_z" {
+		t.Fatalf("operators should run in the declared order, got %v", result)
+	}
+
+	var fromFile Schematics
+	if err := fromFile.LoadJsonSchemaFile("../../test-data/schema/direct/v0/example-1.json"); err != nil {
+		t.Fatal(err)
+	}
+}
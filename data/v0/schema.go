@@ -1,18 +1,21 @@
 package v0
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/ashbeelghouri/jsonschematics/conditions"
 	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"github.com/ashbeelghouri/jsonschematics/metaschema"
 	"github.com/ashbeelghouri/jsonschematics/operators"
 	"github.com/ashbeelghouri/jsonschematics/utils"
 	"github.com/ashbeelghouri/jsonschematics/validators"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
-	"sync"
 )
 
 type TargetKey string
@@ -29,14 +32,80 @@ type Schematics struct {
 	FlatData   map[string]interface{}
 	UnFlatData map[string]interface{}
 	Logging    utils.Logger
+	Execution  Execution
+	// FailOnLintErrors makes loading fail when Check finds errors, custom validators, operators and conditions should be registered before loading
+	FailOnLintErrors bool
+}
+
+type ExecutionStrategy string
+
+const (
+	Sequential ExecutionStrategy = "sequential"
+	Parallel   ExecutionStrategy = "parallel"
+)
+
+// Execution decides how the rows of an array are validated and operated and how the values of a field are validated, the zero value is sequential.
+// Parallel execution uses Workers goroutines (the number of CPUs when not set), the results are always in the order of the input
+type Execution struct {
+	Strategy ExecutionStrategy
+	Workers  int
+}
+
+func (e Execution) workers() int {
+	if e.Strategy != Parallel {
+		return 1
+	}
+	if e.Workers > 0 {
+		return e.Workers
+	}
+	return runtime.NumCPU()
 }
 
 // add this DB to the attributes as SCHEMA_GLOBAL_DB
 
 type Schema struct {
-	Version string                 `json:"version"`
-	Fields  map[TargetKey]Field    `json:"fields"`
-	DB      map[string]interface{} `json:"DB"`
+	Version          string                 `json:"version"`
+	Fields           map[TargetKey]Field    `json:"fields"`
+	DB               map[string]interface{} `json:"DB"`
+	CollectAllErrors bool                   `json:"collect_all_errors"`
+	// Extends is the path of the base schema file, the fields of this schema are merged on it with their merge strategy
+	Extends string `json:"extends"`
+	// Definitions are the fields that are used by the references, e.g. "$ref": "#/definitions/address"
+	Definitions map[string]Field `json:"definitions"`
+	// Strict reports the keys of the data that are not covered by any target, "additional_fields": false does the same
+	Strict           bool  `json:"strict"`
+	AdditionalFields *bool `json:"additional_fields"`
+	// FieldsOrder is the order the fields are declared in, it is kept when the schema is written, the fields missing
+	// from it are written after the others sorted by target
+	FieldsOrder []TargetKey `json:"-"`
+	// Resolved are the extends and $ref that were inlined when the schema was loaded, e.g. "extends base.json", the
+	// schema is not written with them, see CheckStandalone
+	Resolved []string `json:"-"`
+}
+
+// UnmarshalJSON keeps the declared order of the fields
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type schema Schema
+	var decoded schema
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var raw struct {
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err == nil {
+		decoded.FieldsOrder = targetKeys(utils.JsonObjectKeys(raw.Fields))
+	}
+	*s = Schema(decoded)
+	return nil
+}
+
+// allowsAdditionalFields tells if the keys of the data that are not covered by any target are accepted
+func (s Schema) allowsAdditionalFields() bool {
+	if s.Strict {
+		return false
+	}
+	return s.AdditionalFields == nil || *s.AdditionalFields
 }
 
 type Field struct {
@@ -53,12 +122,80 @@ type Field struct {
 	L10n                  map[string]interface{} `json:"l10n"`
 	AdditionalInformation map[string]interface{} `json:"additional_information"`
 	Conditions            map[string]Condition   `json:"conditions"`
-	Tags                  []string               `json:"tags"`
-	Value                 map[string]interface{} `json:"value"`
-	Provided              bool
-	Status                string
-	Errors                errorHandler.Errors
-	logging               utils.Logger
+	// When is an expression over the document, the field is only validated when it is true, e.g. country == "US"
+	When  string                 `json:"when"`
+	Tags  []string               `json:"tags"`
+	Value map[string]interface{} `json:"value"`
+	// Fields is the schema of the object value, the targets are relative to the object
+	Fields map[TargetKey]Field `json:"fields"`
+	// FieldsOrder is the declared order of Fields, see Schema.FieldsOrder
+	FieldsOrder []TargetKey `json:"-"`
+	// Items is the schema of every element of the array value
+	Items *Field `json:"items"`
+	// Merge is the merge strategy of the field when the schema extends a base schema: replace, append or remove.
+	// RemoveValidators and RemoveOperators are removed from the base field when it is appended
+	Merge            string   `json:"merge"`
+	RemoveValidators []string `json:"remove_validators"`
+	RemoveOperators  []string `json:"remove_operators"`
+	// Nullable fields accept null values without running the validators, the other fields with validators or a Type report null
+	Nullable bool `json:"nullable"`
+	// AllowEmpty fields accept empty strings, arrays and objects without running the validators
+	AllowEmpty bool `json:"allow_empty"`
+	// Coerce converts string values to the Type before the validators and operators run, e.g. "42" to 42
+	Coerce bool `json:"coerce"`
+	// Default is set on the target by the operations when the data does not provide it
+	Default interface{} `json:"default"`
+	// CollectAllErrors overrides the option of the schema for this field when it is set
+	CollectAllErrors *bool `json:"collect_all_errors"`
+	// ValidatorsOrder and OperatorsOrder are the order the maps are declared in, names missing from them run after the others sorted by name
+	ValidatorsOrder []string `json:"-"`
+	OperatorsOrder  []string `json:"-"`
+	// ValidatorPipeline and OperatorPipeline run instead of the maps when they are set, they keep the declared order and can repeat a name
+	ValidatorPipeline []Component `json:"-"`
+	OperatorPipeline  []Component `json:"-"`
+	Provided          bool
+	Status            string
+	Errors            errorHandler.Errors
+	logging           utils.Logger
+}
+
+// UnmarshalJSON reads the validators and operators either as a map or as a list of components
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	var decoded struct {
+		field
+		Validators json.RawMessage `json:"validators"`
+		Operators  json.RawMessage `json:"operators"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = Field(decoded.field)
+	var raw struct {
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err == nil {
+		f.FieldsOrder = targetKeys(utils.JsonObjectKeys(raw.Fields))
+	}
+
+	var err error
+	f.Validators, f.ValidatorsOrder, f.ValidatorPipeline, err = parseComponents(decoded.Validators)
+	if err != nil {
+		return fmt.Errorf("invalid validators: %w", err)
+	}
+	f.Operators, f.OperatorsOrder, f.OperatorPipeline, err = parseComponents(decoded.Operators)
+	if err != nil {
+		return fmt.Errorf("invalid operators: %w", err)
+	}
+	return nil
+}
+
+// collectAllErrors tells if all the validators should run on a value instead of stopping at the first failure
+func (f *Field) collectAllErrors(schema Schema) bool {
+	if f.CollectAllErrors != nil {
+		return *f.CollectAllErrors
+	}
+	return schema.CollectAllErrors
 }
 
 func (f *Field) AsMap() *map[string]interface{} {
@@ -76,6 +213,14 @@ func (f *Field) AsMap() *map[string]interface{} {
 
 type Condition struct {
 	Attributes map[string]interface{} `json:"attributes"`
+	// Action is taken instead of skipping the field when the condition fails
+	Action *ConditionalAction `json:"action"`
+}
+
+// ConditionalAction lists the actions taken when the condition passes (Success) or fails (Error), see ConditionActions
+type ConditionalAction struct {
+	Success []string `json:"success"`
+	Error   []string `json:"error"`
 }
 
 type ConstantL10n struct {
@@ -112,15 +257,21 @@ func (s *Schematics) Configs() {
 
 func (s *Schematics) LoadJsonSchemaFile(path string) error {
 	s.Configs()
-	content, err := os.ReadFile(path)
+	schema, err := readSchemaFile(path)
 	if err != nil {
 		s.Logging.ERROR("Failed to load schema file", err)
 		return err
 	}
-	var schema Schema
-	err = json.Unmarshal(content, &schema)
-	if err != nil {
-		s.Logging.ERROR("Failed to unmarshall schema file", err)
+	if err := s.extend(&schema, filepath.Dir(path), []string{path}); err != nil {
+		s.Logging.ERROR("Failed to extend the schema file", err)
+		return err
+	}
+	if err := schema.CheckDependencies(); err != nil {
+		s.Logging.ERROR("Invalid dependencies in the schema", err)
+		return err
+	}
+	if err := schema.CheckExpressions(); err != nil {
+		s.Logging.ERROR("Invalid expressions in the schema", err)
 		return err
 	}
 	s.Logging.DEBUG("Schema Loaded From File: ", schema)
@@ -134,6 +285,12 @@ func (s *Schematics) LoadJsonSchemaFile(path string) error {
 	if s.Locale == "" {
 		s.Locale = "en"
 	}
+	if s.FailOnLintErrors {
+		if err := s.Check(); err != nil {
+			s.Logging.ERROR("Invalid schema", err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -143,12 +300,39 @@ func (s *Schematics) LoadMap(schemaMap interface{}) error {
 		s.Logging.ERROR("Schema should be valid json map[string]interface", err)
 		return err
 	}
+	refs, err := utils.References(JSON)
+	if err != nil {
+		s.Logging.ERROR("Schema should be valid json map[string]interface", err)
+		return err
+	}
+	JSON, err = utils.ResolveReferences(JSON, "")
+	if err != nil {
+		s.Logging.ERROR("Failed to resolve the references of the schema", err)
+		return err
+	}
+	if err := metaschema.Validate("v0", JSON); err != nil {
+		s.Logging.ERROR("Invalid Schema", err)
+		return err
+	}
 	var schema Schema
 	err = json.Unmarshal(JSON, &schema)
 	if err != nil {
 		s.Logging.ERROR("Invalid Schema", err)
 		return err
 	}
+	schema.Resolved = ResolvedReferences(refs)
+	if err := s.extend(&schema, "", nil); err != nil {
+		s.Logging.ERROR("Failed to extend the schema", err)
+		return err
+	}
+	if err := schema.CheckDependencies(); err != nil {
+		s.Logging.ERROR("Invalid dependencies in the schema", err)
+		return err
+	}
+	if err := schema.CheckExpressions(); err != nil {
+		s.Logging.ERROR("Invalid expressions in the schema", err)
+		return err
+	}
 	s.Logging.DEBUG("Schema Loaded From MAP: ", schema)
 	s.Schema = schema
 	s.Validators.BasicValidators()
@@ -160,97 +344,108 @@ func (s *Schematics) LoadMap(schemaMap interface{}) error {
 	if s.Locale == "" {
 		s.Locale = "en"
 	}
+	if s.FailOnLintErrors {
+		if err := s.Check(); err != nil {
+			s.Logging.ERROR("Invalid schema", err)
+			return err
+		}
+	}
 	return nil
 }
 
-// if validators >>> if passed then do *
-
-func fnExists(name string, allValidators map[string]validators.Validator) bool {
-	_, exists := allValidators[name]
-	if !exists {
-		return false
+// readSchemaFile reads the schema file and resolves its references
+func readSchemaFile(path string) (Schema, error) {
+	var schema Schema
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return schema, err
 	}
-	return true
+	refs, err := utils.References(content)
+	if err != nil {
+		return schema, err
+	}
+	content, err = utils.ResolveReferences(content, filepath.Dir(path))
+	if err != nil {
+		return schema, err
+	}
+	if err := metaschema.Validate("v0", content); err != nil {
+		return schema, err
+	}
+	err = json.Unmarshal(content, &schema)
+	schema.Resolved = ResolvedReferences(refs)
+	return schema, err
 }
 
-func (f *Field) validateSingleFieldValue(targetID interface{}, value interface{}, allValidators map[string]validators.Validator, db map[string]interface{}, wg *sync.WaitGroup, errChan chan *errorHandler.Error) {
-	defer wg.Done()
-
-	var errorMessage errorHandler.Error
-	for name, constants := range f.Validators {
-		// Early exit if validation name is empty, excluded, or not found in allValidators
-		if name == "" || utils.StringInStrings(strings.ToUpper(name), utils.ExcludedValidators) || !fnExists(name, allValidators) {
-			continue
-		}
-
-		errorMessage.ID = targetID
-		errorMessage.Value = value
-		errorMessage.Validator = name
-
-		// Set up attributes for validation
-		if constants.Attributes == nil {
-			constants.Attributes = make(map[string]interface{})
-		}
-		constants.Attributes["DB"] = db
-
-		// Execute the validator function
-		fn := allValidators[name]
-		err := fn(value, constants.Attributes)
-
-		// Handle validation errors
-		if err != nil {
-			// Set custom error message if available
-			if constants.Error != "" {
-				errorMessage.AddMessage("en", constants.Error)
-			}
+// ResolvedReferences are the Resolved entries of the $ref
+func ResolvedReferences(refs []string) []string {
+	var resolved []string
+	for _, ref := range refs {
+		resolved = append(resolved, "$ref "+ref)
+	}
+	return resolved
+}
 
-			// Handle localization (L10n) if present
-			if f.L10n != nil {
-				for locale, msg := range constants.L10n.Error {
-					if msg != nil {
-						errorMessage.AddMessage(locale, msg.(string))
-					}
-				}
-				for locale, nameValue := range constants.L10n.Name {
-					if nameValue != nil {
-						errorMessage.AddL10n(name, locale, nameValue.(string))
-					}
-				}
-			}
+// CheckStandalone returns an error when the schema was loaded with extends or $ref. They were inlined when loading, so
+// the schema would be written as a standalone file that does not extend or reference the other files anymore, set
+// Resolved to nil to write it anyway
+func (s Schema) CheckStandalone() error {
+	if len(s.Resolved) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the schema was loaded with %s, they are inlined and would be written as a standalone schema", strings.Join(s.Resolved, ", "))
+}
 
-			// Send the error message to the error channel
-			errChan <- &errorMessage
-			return // Exit after handling the first error
+// extend merges the schema on the base schema file it extends, the path of the base schema is relative to dir
+// and chain has the files that are already extended
+func (s *Schematics) extend(schema *Schema, dir string, chain []string) error {
+	if schema.Extends == "" {
+		return nil
+	}
+	path := schema.Extends
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	for _, extended := range chain {
+		if filepath.Clean(extended) == filepath.Clean(path) {
+			return fmt.Errorf("circular extends: %s", strings.Join(append(chain, path), " -> "))
 		}
 	}
-
-	// If no error, return nil to signal success
-	errChan <- nil
+	base, err := readSchemaFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to load the base schema %s: %w", schema.Extends, err)
+	}
+	if err := s.extend(&base, filepath.Dir(path), append(chain, path)); err != nil {
+		return err
+	}
+	merged, conflicts := MergeSchemas(base, *schema)
+	for _, conflict := range conflicts {
+		s.Logging.DEBUG("merge conflict", conflict.String())
+	}
+	merged.Resolved = append([]string{"extends " + schema.Extends}, merged.Resolved...)
+	*schema = merged
+	return nil
 }
 
+// if validators >>> if passed then do *
+
 func (f *Field) Validate(allValidators map[string]validators.Validator, id *string, db map[string]interface{}) error {
+	return f.ValidateContext(context.Background(), validators.Validators{ValidationFns: allValidators}, id, db)
+}
+
+// ValidateContext validates all the values of the field one by one, it returns the context error when ctx is done before all the values are validated
+func (f *Field) ValidateContext(ctx context.Context, allValidators validators.Validators, id *string, db map[string]interface{}) error {
 	if f.Validators == nil {
 		return errors.New("no validators defined")
 	}
-	errorChannel := make(chan *errorHandler.Error)
-	var wg sync.WaitGroup
-	for targetID, value := range f.Value {
-		wg.Add(1)
-		go f.validateSingleFieldValue(targetID, value, allValidators, db, &wg, errorChannel)
-	}
-
-	go func() {
-		wg.Wait()
-		close(errorChannel)
-	}()
-
-	for vErr := range errorChannel {
-		if vErr != nil {
-			f.Errors.AddError(f.Target, *vErr)
-			f.Status = "failed"
-		}
+	s := Schematics{Validators: allValidators, Logging: f.logging}
+	cf, _ := s.compileField(f.Target, *f)
+	c := CompiledSchematics{separator: ".", logging: f.logging}
+	errs := c.validateField(ctx, cf, f.Value, &validation{id: id, db: db, document: f.Value}, fieldState{}, 1)
+	if errs.HasErrors() {
+		f.Errors.MergeErrors(errs)
+		f.Status = "failed"
 	}
-	return nil
+	return ctx.Err()
 }
 
 func (s *Schematics) makeFlat(data map[string]interface{}) *map[string]interface{} {
@@ -263,12 +458,6 @@ func (s *Schematics) makeFlat(data map[string]interface{}) *map[string]interface
 	return &dMap.Data
 }
 
-func (s *Schematics) deflate(data map[string]interface{}) map[string]interface{} {
-	unFlatData := utils.DeflateMap(data, s.Separator)
-	s.UnFlatData = unFlatData
-	return unFlatData
-}
-
 func (s *Schematics) AssignData(data map[string]interface{}) error {
 	flatData := s.makeFlat(data)
 	log.Println("successfully transformed to flat data:", *flatData)
@@ -297,7 +486,22 @@ func (s *Schematics) AssignData(data map[string]interface{}) error {
 	return nil
 }
 
+func contextErrors(err error) *errorHandler.Errors {
+	var baseError errorHandler.Error
+	var errs errorHandler.Errors
+	baseError.Validator = "context"
+	baseError.AddMessage("en", err.Error())
+	errs.AddError("whole-data", baseError)
+	return &errs
+}
+
+// Validate compiles the schema on every call, the hot paths should call Compile once and validate with the CompiledSchematics
 func (s *Schematics) Validate(jsonData interface{}) *errorHandler.Errors {
+	return s.ValidateContext(context.Background(), jsonData)
+}
+
+// ValidateContext validates the object or array of objects, validation stops when ctx is cancelled or its deadline is exceeded
+func (s *Schematics) ValidateContext(ctx context.Context, jsonData interface{}) *errorHandler.Errors {
 	var baseError errorHandler.Error
 	var errs errorHandler.Errors
 	baseError.Validator = "validate-object"
@@ -306,25 +510,8 @@ func (s *Schematics) Validate(jsonData interface{}) *errorHandler.Errors {
 		errs.AddError("whole-data", baseError)
 		return &errs
 	}
-
-	dataBytes, err := json.Marshal(jsonData)
-	if err != nil {
-		baseError.AddMessage("en", "data is not valid json")
-		errs.AddError("whole-data", baseError)
-		return &errs
-	}
-
-	var obj map[string]interface{}
-	var arr []map[string]interface{}
-	if err := json.Unmarshal(dataBytes, &obj); err == nil {
-		return s.ValidateObject(&obj, nil)
-	} else if err := json.Unmarshal(dataBytes, &arr); err == nil {
-		return s.ValidateArray(arr)
-	} else {
-		baseError.AddMessage("en", "invalid format provided for the data, can only be map[string]interface or []map[string]interface")
-		errs.AddError("whole-data", baseError)
-		return &errs
-	}
+	compiled, _ := s.compile(false)
+	return compiled.ValidateContext(ctx, jsonData)
 }
 
 func (s *Schematics) GetValidatedFieldTargets() []string {
@@ -355,58 +542,14 @@ func (f *Field) ConditionalPassage(allConditions conditions.Conditions, schema S
 	return true
 }
 
+// ValidateObject compiles the schema on every call like Validate
 func (s *Schematics) ValidateObject(jsonData *map[string]interface{}, id *string) *errorHandler.Errors {
-	var errorMessages errorHandler.Errors
-	var baseError errorHandler.Error
-	flatData := *s.makeFlat(*jsonData)
-	err := s.AssignData(flatData)
-	if err != nil {
-		baseError.AddMessage("en", err.Error())
-		errorMessages.AddError("flattening", baseError)
-		return &errorMessages
-	}
-
-	uniqueID := ""
-
-	if id != nil {
-		uniqueID = *id
-	}
-	db := s.Schema.GetDB(flatData)
-	targets := s.GetValidatedFieldTargets()
-
-	for target, field := range s.Schema.Fields {
-		if !field.ConditionalPassage(s.Conditions, s.Schema) {
-			continue
-		}
-
-		if len(field.DependsOn) > 0 {
-			missingDependencies := utils.FindUniqueElements(field.DependsOn, targets)
-			if len(missingDependencies) > 0 {
-				baseError.AddMessage("en", fmt.Sprintf("missing dependencies (%s) for %s", target, strings.Join(missingDependencies, ",")))
-				continue
-			}
-		}
-
-		field.Target = string(target)
-		field.logging = s.Logging
-		baseError.Validator = "is-required"
-		if field.IsRequired && !field.Provided {
-			baseError.AddMessage("en", "please provide the value for this required field")
-		}
-		err := field.Validate(s.Validators.ValidationFns, &uniqueID, db)
-		if err != nil {
-			baseError.Validator = "common"
-			baseError.AddMessage("en", err.Error())
-		}
-		if field.Errors.HasErrors() {
-			errorMessages.MergeErrors(&field.Errors)
-		}
-	}
+	return s.ValidateObjectContext(context.Background(), jsonData, id)
+}
 
-	if errorMessages.HasErrors() {
-		return &errorMessages
-	}
-	return nil
+func (s *Schematics) ValidateObjectContext(ctx context.Context, jsonData *map[string]interface{}, id *string) *errorHandler.Errors {
+	compiled, _ := s.compile(false)
+	return compiled.ValidateObjectContext(ctx, jsonData, id)
 }
 
 // GetDB Corrected and completed function
@@ -432,33 +575,15 @@ func (s *Schema) GetDB(flatData map[string]interface{}) map[string]interface{} {
 	return db
 }
 
+// ValidateArray compiles the schema on every call like Validate
 func (s *Schematics) ValidateArray(jsonData []map[string]interface{}) *errorHandler.Errors {
-	s.Logging.DEBUG("validating the array")
-	var errs errorHandler.Errors
-	i := 0
-	for _, d := range jsonData {
-		var errorMessages *errorHandler.Errors
-		var dMap utils.DataMap
-		dMap.FlattenTheMap(d, "", s.Separator)
-		arrayId, exists := dMap.Data[s.ArrayIdKey]
-		if !exists {
-			arrayId = fmt.Sprintf("row-%d", i)
-			exists = true
-		}
-
-		id := arrayId.(string)
-		errorMessages = s.ValidateObject(&d, &id)
-		if errorMessages.HasErrors() {
-			s.Logging.ERROR("has errors", errorMessages.GetStrings("en", "%data\n"))
-			errs.MergeErrors(errorMessages)
-		}
-		i = i + 1
-	}
+	return s.ValidateArrayContext(context.Background(), jsonData)
+}
 
-	if errs.HasErrors() {
-		return &errs
-	}
-	return nil
+// ValidateArrayContext validates the rows one by one and stops at the first row reached after ctx is done
+func (s *Schematics) ValidateArrayContext(ctx context.Context, jsonData []map[string]interface{}) *errorHandler.Errors {
+	compiled, _ := s.compile(false)
+	return compiled.ValidateArrayContext(ctx, jsonData)
 }
 
 // operators
@@ -478,77 +603,44 @@ func (f *Field) Operate(value interface{}, allOperations map[string]operators.Op
 	return value
 }
 
+// Operate compiles the schema on every call, the hot paths should call Compile once and operate with the CompiledSchematics
 func (s *Schematics) Operate(data interface{}) (interface{}, *errorHandler.Errors) {
-	var errorMessages errorHandler.Errors
-	var baseError errorHandler.Error
-	baseError.Validator = "operate-on-schema"
-	bytes, err := json.Marshal(data)
-	if err != nil {
-		s.Logging.ERROR("[operate] error converting the data into bytes", err)
-		baseError.AddMessage("en", "data is not valid json")
-		errorMessages.AddError("whole-data", baseError)
-		return nil, &errorMessages
-	}
-
-	dataType, item := utils.IsValidJson(bytes)
-	if item == nil {
-		s.Logging.ERROR("[operate] error occurred when checking if this data is an array or object")
-		baseError.AddMessage("en", "can not convert the data into json")
-		errorMessages.AddError("whole-data", baseError)
-		return nil, &errorMessages
-	}
-
-	if dataType == "object" {
-		obj := item.(map[string]interface{})
-		results := s.OperateOnObject(obj)
-		if results != nil {
-			return results, nil
-		} else {
-			baseError.AddMessage("en", "operation on object unsuccessful")
-			errorMessages.AddError("whole-data", baseError)
-			return nil, &errorMessages
-		}
-	} else if dataType == "array" {
-		arr := item.([]map[string]interface{})
-		results := s.OperateOnArray(arr)
-		if results != nil && len(*results) > 0 {
-			return results, nil
-		} else {
-			baseError.AddMessage("en", "operation on array unsuccessful")
-			errorMessages.AddError("whole-data", baseError)
-			return nil, &errorMessages
-		}
-	}
+	return s.OperateContext(context.Background(), data)
+}
 
-	return data, nil
+// OperateContext performs the operations on the object or array of objects until ctx is done
+func (s *Schematics) OperateContext(ctx context.Context, data interface{}) (interface{}, *errorHandler.Errors) {
+	compiled, _ := s.compile(false)
+	return compiled.OperateContext(ctx, data)
 }
 
+// OperateOnObject compiles the schema on every call like Operate
 func (s *Schematics) OperateOnObject(data map[string]interface{}) *map[string]interface{} {
-	data = *s.makeFlat(data)
-	for target, field := range s.Schema.Fields {
-		matchingKeys := utils.FindMatchingKeys(data, string(target), s.Separator)
-		for key, value := range matchingKeys {
-			data[key] = field.Operate(value, s.Operators.OpFunctions)
-		}
-	}
-	d := s.deflate(data)
-	return &d
+	return s.OperateOnObjectContext(context.Background(), data)
+}
+
+// OperateOnObjectContext returns nil when ctx is done before all the fields are operated
+func (s *Schematics) OperateOnObjectContext(ctx context.Context, data map[string]interface{}) *map[string]interface{} {
+	compiled, _ := s.compile(false)
+	return compiled.OperateOnObjectContext(ctx, data)
 }
 
+// OperateOnArray compiles the schema on every call like Operate
 func (s *Schematics) OperateOnArray(data []map[string]interface{}) *[]map[string]interface{} {
-	var obj []map[string]interface{}
-	for _, d := range data {
-		results := s.OperateOnObject(d)
-		obj = append(obj, *results)
-	}
-	if len(obj) > 0 {
-		return &obj
-	}
-	return nil
+	return s.OperateOnArrayContext(context.Background(), data)
+}
+
+// OperateOnArrayContext returns nil when ctx is done before all the rows are operated
+func (s *Schematics) OperateOnArrayContext(ctx context.Context, data []map[string]interface{}) *[]map[string]interface{} {
+	compiled, _ := s.compile(false)
+	return compiled.OperateOnArrayContext(ctx, data)
 }
 
 // General
 
+// MergeFields only adds the fields of sc2 that have no type in s, it ignores the merge strategies, the definitions and the order of the fields.
+//
+// Deprecated: use Merge, which merges with the strategies of the overlay and reports the conflicts.
 func (s *Schematics) MergeFields(sc2 *Schematics) *Schematics {
 	for target, field := range sc2.Schema.Fields {
 		if s.Schema.Fields[target].Type == "" {
@@ -0,0 +1,32 @@
+package v0
+
+import (
+	"testing"
+)
+
+func TestLoadMapErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		schematics Schematics
+		fields     map[string]interface{}
+		expected   string
+	}{
+		{
+			name:     "meta schema",
+			fields:   map[string]interface{}{"user.name": map[string]interface{}{"required": "yes", "validators": "IsString"}},
+			expected: "the schema file is not valid: fields[user.name].required should be a boolean, fields[user.name].validators should be an object or an array",
+		},
+		{
+			name:       "lint errors",
+			schematics: Schematics{FailOnLintErrors: true},
+			fields:     map[string]interface{}{"name": map[string]interface{}{"validators": map[string]interface{}{"MaxLengthAllowed": map[string]interface{}{}}}},
+			expected:   "the schema has errors: fields[name].validators[MaxLengthAllowed].attributes: max attribute is required by MaxLengthAllowed",
+		},
+	}
+	for _, test := range tests {
+		err := test.schematics.LoadMap(map[string]interface{}{"version": "0", "fields": test.fields})
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package v0
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"io"
+)
+
+// RowResult is the validation result of a single row read from the stream, Line is only set for json lines
+type RowResult struct {
+	Index  int
+	Line   int
+	ID     string
+	Errors *errorHandler.Errors
+}
+
+// ValidateStream validates the json array read from r one row at a time, so only a single row is kept in memory.
+// fn is called with the result of every row in the order of the rows, returning an error from fn stops the stream
+func (s *Schematics) ValidateStream(ctx context.Context, r io.Reader, fn func(RowResult) error) error {
+	compiled, _ := s.compile(false)
+	return compiled.ValidateStream(ctx, r, fn)
+}
+
+func (c *CompiledSchematics) ValidateStream(ctx context.Context, r io.Reader, fn func(RowResult) error) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("unable to read the stream: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return errors.New("stream should contain a json array")
+	}
+
+	for index := 0; decoder.More(); index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var item interface{}
+		if err := decoder.Decode(&item); err != nil {
+			return fmt.Errorf("unable to read row %d: %w", index, err)
+		}
+
+		result := RowResult{Index: index}
+		row, ok := item.(map[string]interface{})
+		if ok {
+			result.ID = c.rowID(row, fmt.Sprintf("row-%d", index))
+			result.Errors = c.ValidateObjectContext(ctx, &row, &result.ID)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		} else {
+			result.ID = fmt.Sprintf("row-%d", index)
+			result.Errors = rowFormatErrors(result.ID, item, "invalid format provided for the row, can only be map[string]interface")
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("unable to read the end of the stream: %w", err)
+	}
+	return nil
+}
+
+// ValidateJsonLines validates newline-delimited json (json lines), every line is a single object validated on its own.
+// Blank lines are skipped, lines that are not json objects are reported as errors of that line
+func (s *Schematics) ValidateJsonLines(ctx context.Context, r io.Reader, fn func(RowResult) error) error {
+	compiled, _ := s.compile(false)
+	return compiled.ValidateJsonLines(ctx, r, fn)
+}
+
+func (c *CompiledSchematics) ValidateJsonLines(ctx context.Context, r io.Reader, fn func(RowResult) error) error {
+	reader := bufio.NewReader(r)
+	index := 0
+	for lineNumber := 1; ; lineNumber++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("unable to read line %d: %w", lineNumber, readErr)
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			result := RowResult{Index: index, Line: lineNumber}
+			var row map[string]interface{}
+			if err := json.Unmarshal(line, &row); err == nil && row != nil {
+				result.ID = c.rowID(row, fmt.Sprintf("line-%d", lineNumber))
+				result.Errors = c.ValidateObjectContext(ctx, &row, &result.ID)
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			} else {
+				result.ID = fmt.Sprintf("line-%d", lineNumber)
+				result.Errors = rowFormatErrors(result.ID, string(line), "invalid format provided for the line, can only be a json object")
+			}
+			result.Errors.SetLine(lineNumber)
+
+			if err := fn(result); err != nil {
+				return err
+			}
+			index++
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+func rowFormatErrors(id string, value interface{}, message string) *errorHandler.Errors {
+	var baseError errorHandler.Error
+	var errs errorHandler.Errors
+	baseError.Validator = "validate-object"
+	baseError.ID = id
+	baseError.Value = value
+	baseError.AddMessage("en", message)
+	errs.AddError("whole-data", baseError)
+	return &errs
+}
@@ -0,0 +1,157 @@
+package v0
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateStream(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"user.profile.age": map[string]interface{}{
+				"validators": map[string]interface{}{"MaxAllowed": map[string]interface{}{"attributes": map[string]interface{}{"max": 11}}},
+			},
+		},
+	})
+	schematics.ArrayIdKey = "user.id"
+	file, err := os.Open("../../test-data/data/direct/example-2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var ids []string
+	var failed []string
+	err = schematics.ValidateStream(context.Background(), file, func(result RowResult) error {
+		ids = append(ids, result.ID)
+		if result.Errors.HasErrors() {
+			failed = append(failed, result.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Fatalf("rows should be identified by the array id key in order, got %v", ids)
+	}
+	if strings.Join(failed, ",") != "2" {
+		t.Fatalf("only the row with id 2 should fail, got %v", failed)
+	}
+
+	err = schematics.ValidateStream(context.Background(), strings.NewReader(`{"user": {}}`), func(RowResult) error { return nil })
+	if err == nil {
+		t.Fatal("expected an error for a stream that is not an array")
+	}
+}
+
+func TestValidateJsonLines(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"age": map[string]interface{}{
+				"validators": map[string]interface{}{"MaxAllowed": map[string]interface{}{"attributes": map[string]interface{}{"max": 20}}},
+			},
+		},
+	})
+	schematics.ArrayIdKey = "id"
+	lines := "{\"id\": \"a\", \"age\": 10}\n\n{\"age\": 30}\nnot json\n{\"id\": \"d\", \"age\": 40}"
+
+	failed := map[int]string{}
+	err := schematics.ValidateJsonLines(context.Background(), strings.NewReader(lines), func(result RowResult) error {
+		if result.Errors.HasErrors() {
+			failed[result.Line] = result.ID
+			for _, e := range result.Errors.Messages {
+				if e.Line != result.Line {
+					t.Errorf("error should carry line %d, got %d", result.Line, e.Line)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int]string{3: "line-3", 4: "line-4", 5: "d"}
+	if fmt.Sprint(failed) != fmt.Sprint(expected) {
+		t.Fatalf("expected failures %v, got %v", expected, failed)
+	}
+}
+
+func TestParallelValidateArrayKeepsRowOrder(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"age": map[string]interface{}{
+				"validators": map[string]interface{}{"MaxAllowed": map[string]interface{}{"attributes": map[string]interface{}{"max": 0}}},
+			},
+		},
+	})
+	schematics.ArrayIdKey = "id"
+	schematics.Execution = Execution{Strategy: Parallel, Workers: 4}
+	var rows []map[string]interface{}
+	var expected []string
+	for i := 0; i < 50; i++ {
+		rows = append(rows, map[string]interface{}{"id": fmt.Sprintf("r%02d", i), "age": i + 1})
+		expected = append(expected, fmt.Sprintf("r%02d", i))
+	}
+
+	errs := schematics.ValidateArray(rows)
+	var got []string
+	for _, target := range errs.Targets() {
+		got = append(got, fmt.Sprint(errs.Messages[target].ID))
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("errors should be in the order of the rows, got %v", got)
+	}
+}
+
+func TestParallelOperateOnArrayKeepsRowOrder(t *testing.T) {
+	schematics := loadSchema(t, map[string]interface{}{
+		"version": "0",
+		"fields": map[string]interface{}{
+			"name": map[string]interface{}{
+				"operators": []interface{}{map[string]interface{}{"name": "UpperCase"}, map[string]interface{}{"name": "Track"}},
+			},
+		},
+	})
+	schematics.Execution = Execution{Strategy: Parallel, Workers: 4}
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	schematics.Operators.RegisterOperation("Track", func(i interface{}, attributes map[string]interface{}) *interface{} {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return &i
+	})
+
+	var rows []map[string]interface{}
+	for i := 0; i < 20; i++ {
+		rows = append(rows, map[string]interface{}{"name": fmt.Sprintf("row %02d", i)})
+	}
+	results := schematics.OperateOnArray(rows)
+	if results == nil || len(*results) != len(rows) {
+		t.Fatalf("expected %d rows, got %v", len(rows), results)
+	}
+	for i, row := range *results {
+		if expected := fmt.Sprintf("ROW %02d", i); row["name"] != expected {
+			t.Fatalf("expected %s at %d, got %v", expected, i, row["name"])
+		}
+	}
+	if maxRunning < 2 {
+		t.Fatal("expected the rows to be operated in parallel")
+	}
+}
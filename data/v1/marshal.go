@@ -0,0 +1,211 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"os"
+	"sort"
+)
+
+// Marshal returns the schema of the schematics in the v1 file format as indented JSON. The fields, validators and
+// operators keep their order, so loading the result gives the same schematics. Conditions, tags and components that
+// appear more than once do not exist in v1, a schema with them is not marshaled. A schema loaded with
+// extends or $ref is not marshaled either, see v0.Schema.CheckStandalone
+func Marshal(schematics *v0.Schematics) ([]byte, error) {
+	if err := schematics.Schema.CheckStandalone(); err != nil {
+		schematics.Logging.ERROR("The schema can not be written", err)
+		return nil, err
+	}
+	schema, err := fromSchema(schematics.Schema)
+	if err != nil {
+		schematics.Logging.ERROR("The schema can not be written in the v1 format", err)
+		return nil, err
+	}
+	content, err := utils.MarshalIndent(schema.fileObject())
+	if err != nil {
+		schematics.Logging.ERROR("Failed to marshal the schema", err)
+		return nil, err
+	}
+	return content, nil
+}
+
+// Save writes the schema of the schematics to the file in the v1 file format, see Marshal
+func Save(schematics *v0.Schematics, path string) error {
+	content, err := Marshal(schematics)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		schematics.Logging.ERROR("Failed to save the schema file", err)
+		return err
+	}
+	return nil
+}
+
+// fromSchema converts the v0 schema back into the v1 schema, it is the reverse of transformSchema
+func fromSchema(baseSchema v0.Schema) (Schema, error) {
+	schema := Schema{
+		Version:          baseSchema.Version,
+		DB:               baseSchema.DB,
+		CollectAllErrors: baseSchema.CollectAllErrors,
+		Strict:           baseSchema.Strict,
+		AdditionalFields: baseSchema.AdditionalFields,
+		Extends:          baseSchema.Extends,
+	}
+	var err error
+	schema.Fields, err = fromFields(baseSchema.Fields, baseSchema.FieldsOrder)
+	if err != nil {
+		return schema, err
+	}
+	if len(baseSchema.Definitions) > 0 {
+		schema.Definitions = make(map[string]Field, len(baseSchema.Definitions))
+		for name, definition := range baseSchema.Definitions {
+			if schema.Definitions[name], err = fromField("", definition); err != nil {
+				return schema, fmt.Errorf("definitions.%s: %w", name, err)
+			}
+		}
+	}
+	return schema, nil
+}
+
+func fromFields(baseFields map[v0.TargetKey]v0.Field, order []v0.TargetKey) ([]Field, error) {
+	if baseFields == nil {
+		return nil, nil
+	}
+	fields := make([]Field, 0, len(baseFields))
+	for _, target := range v0.OrderedTargets(baseFields, order) {
+		field, err := fromField(string(target), baseFields[target])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", target, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func fromField(target string, baseField v0.Field) (Field, error) {
+	if len(baseField.Tags) > 0 {
+		return Field{}, errors.New("tags are not supported by the v1 format")
+	}
+	if len(baseField.Conditions) > 0 {
+		return Field{}, errors.New("conditions are not supported by the v1 format")
+	}
+	field := Field{
+		DependsOn:             baseField.DependsOn,
+		DisplayName:           baseField.DisplayName,
+		Name:                  baseField.Name,
+		TargetKey:             target,
+		AddToDB:               baseField.AddToDB,
+		Type:                  baseField.Type,
+		IsRequired:            baseField.IsRequired,
+		Description:           baseField.Description,
+		L10n:                  baseField.L10n,
+		AdditionalInformation: baseField.AdditionalInformation,
+		CollectAllErrors:      baseField.CollectAllErrors,
+		Nullable:              baseField.Nullable,
+		AllowEmpty:            baseField.AllowEmpty,
+		Default:               baseField.Default,
+		Coerce:                baseField.Coerce,
+		Merge:                 baseField.Merge,
+		RemoveValidators:      baseField.RemoveValidators,
+		RemoveOperators:       baseField.RemoveOperators,
+		When:                  baseField.When,
+	}
+	var err error
+	if field.Validators, field.validatorsOrder, err = fromComponents(baseField.ValidatorComponents()); err != nil {
+		return field, fmt.Errorf("validators: %w", err)
+	}
+	if field.Operators, field.operatorsOrder, err = fromComponents(baseField.OperatorComponents()); err != nil {
+		return field, fmt.Errorf("operators: %w", err)
+	}
+	if len(baseField.Fields) > 0 {
+		if field.Fields, err = fromFields(baseField.Fields, baseField.FieldsOrder); err != nil {
+			return field, err
+		}
+	}
+	if baseField.Items != nil {
+		items, err := fromField("", *baseField.Items)
+		if err != nil {
+			return field, fmt.Errorf("items: %w", err)
+		}
+		field.Items = &items
+	}
+	return field, nil
+}
+
+// fromComponents returns the map of the components and their order
+func fromComponents(baseComponents []v0.Component) (map[string]Component, []string, error) {
+	if len(baseComponents) == 0 {
+		return nil, nil, nil
+	}
+	components := make(map[string]Component, len(baseComponents))
+	var order []string
+	for _, c := range baseComponents {
+		if _, exists := components[c.Name]; exists {
+			return nil, nil, fmt.Errorf("%s appears more than once, a pipeline is not supported by the v1 format", c.Name)
+		}
+		components[c.Name] = Component{
+			Attributes: c.Attributes,
+			Error:      c.Error,
+			L10n:       ComponentLocal{Name: c.L10n.Name, Error: c.L10n.Error},
+		}
+		order = append(order, c.Name)
+	}
+	return components, order, nil
+}
+
+// fileObject is the schema as it is written in the file, the validators and operators are written in their order
+func (s Schema) fileObject() *utils.OrderedObject {
+	file := utils.CompactJson(s).(*utils.OrderedObject)
+	if s.Fields != nil {
+		file.Set("fields", fileFields(s.Fields))
+	}
+	if s.Definitions != nil {
+		names := make([]string, 0, len(s.Definitions))
+		for name := range s.Definitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		definitions := utils.NewOrderedObject()
+		for _, name := range names {
+			definitions.Set(name, s.Definitions[name].fileObject())
+		}
+		file.Set("definitions", definitions)
+	}
+	return file
+}
+
+func fileFields(fields []Field) []interface{} {
+	file := make([]interface{}, len(fields))
+	for i, field := range fields {
+		file[i] = field.fileObject()
+	}
+	return file
+}
+
+func (f Field) fileObject() *utils.OrderedObject {
+	file := utils.CompactJson(f).(*utils.OrderedObject)
+	if f.Validators != nil {
+		file.Set("validators", fileComponents(f.Validators, f.validatorsOrder))
+	}
+	if f.Operators != nil {
+		file.Set("operators", fileComponents(f.Operators, f.operatorsOrder))
+	}
+	if f.Fields != nil {
+		file.Set("fields", fileFields(f.Fields))
+	}
+	if f.Items != nil {
+		file.Set("items", f.Items.fileObject())
+	}
+	return file
+}
+
+func fileComponents(components map[string]Component, order []string) *utils.OrderedObject {
+	file := utils.NewOrderedObject()
+	for _, name := range order {
+		file.Set(name, utils.CompactJson(components[name]))
+	}
+	return file
+}
@@ -0,0 +1,62 @@
+package v1
+
+import (
+	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files, _ := filepath.Glob("../../test-data/schema/direct/v1/*.json")
+	for _, file := range files {
+		loaded, err := LoadJsonSchemaFile(file)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		saved := filepath.Join(dir, filepath.Base(file))
+		if err := Save(loaded, saved); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		reloaded, err := LoadJsonSchemaFile(saved)
+		if err != nil {
+			t.Fatalf("%s: %v", saved, err)
+		}
+		if !reflect.DeepEqual(loaded.Schema, reloaded.Schema) {
+			t.Fatalf("%s changed after saving it:\n%+v\n%+v", file, loaded.Schema, reloaded.Schema)
+		}
+		resaved := saved + ".again"
+		if err := Save(reloaded, resaved); err != nil {
+			t.Fatal(err)
+		}
+		first, _ := os.ReadFile(saved)
+		second, _ := os.ReadFile(resaved)
+		if string(first) != string(second) {
+			t.Fatalf("%s is not saved the same way twice:\n%s\n%s", file, first, second)
+		}
+	}
+}
+
+func TestMarshalUnsupportedSchemas(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"pipeline": {"name": map[string]interface{}{"validators": []interface{}{
+			map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "^a"}},
+			map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "z$"}},
+		}}},
+		"conditions": {"age": map[string]interface{}{
+			"conditions": map[string]interface{}{"FieldExists": map[string]interface{}{"attributes": map[string]interface{}{"field": "name"}}},
+		}},
+	}
+	for name, fields := range tests {
+		var schematics v0.Schematics
+		if err := schematics.LoadMap(map[string]interface{}{"version": "0", "fields": fields}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Marshal(&schematics); err == nil || !strings.Contains(err.Error(), "not supported by the v1 format") {
+			t.Errorf("%s: expected the schema not to be written in v1, got %v", name, err)
+		}
+	}
+}
@@ -2,12 +2,16 @@ package v1
 
 import (
 	"encoding/json"
+	"fmt"
 	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/metaschema"
 	"github.com/ashbeelghouri/jsonschematics/operators"
 	"github.com/ashbeelghouri/jsonschematics/utils"
 	"github.com/ashbeelghouri/jsonschematics/validators"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 var Logs utils.Logger
@@ -24,9 +28,16 @@ type Schematics struct {
 }
 
 type Schema struct {
-	Version string                 `json:"version"`
-	Fields  []Field                `json:"fields"`
-	DB      map[string]interface{} `json:"DB"`
+	Version          string                 `json:"version"`
+	Fields           []Field                `json:"fields"`
+	DB               map[string]interface{} `json:"DB"`
+	CollectAllErrors bool                   `json:"collect_all_errors"`
+	Strict           bool                   `json:"strict"`
+	AdditionalFields *bool                  `json:"additional_fields"`
+	Definitions      map[string]Field       `json:"definitions"`
+	Extends          string                 `json:"extends"`
+	// resolved are the v0.Schema Resolved entries of the $ref that were inlined when the schema was read
+	resolved []string
 }
 
 type Field struct {
@@ -42,6 +53,37 @@ type Field struct {
 	Operators             map[string]Component   `json:"operators"`
 	L10n                  map[string]interface{} `json:"l10n"`
 	AdditionalInformation map[string]interface{} `json:"additional_information"`
+	CollectAllErrors      *bool                  `json:"collect_all_errors"`
+	Nullable              bool                   `json:"nullable"`
+	AllowEmpty            bool                   `json:"allow_empty"`
+	Default               interface{}            `json:"default"`
+	Coerce                bool                   `json:"coerce"`
+	Fields                []Field                `json:"fields"`
+	Items                 *Field                 `json:"items"`
+	Merge                 string                 `json:"merge"`
+	RemoveValidators      []string               `json:"remove_validators"`
+	RemoveOperators       []string               `json:"remove_operators"`
+	When                  string                 `json:"when"`
+	validatorsOrder       []string
+	operatorsOrder        []string
+}
+
+func (f *Field) UnmarshalJSON(data []byte) error {
+	type field Field
+	var decoded field
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var raw struct {
+		Validators json.RawMessage `json:"validators"`
+		Operators  json.RawMessage `json:"operators"`
+	}
+	if err := json.Unmarshal(data, &raw); err == nil {
+		decoded.validatorsOrder = utils.JsonObjectKeys(raw.Validators)
+		decoded.operatorsOrder = utils.JsonObjectKeys(raw.Operators)
+	}
+	*f = Field(decoded)
+	return nil
 }
 
 type ComponentLocal struct {
@@ -70,40 +112,147 @@ func (s *Schematics) Configs() {
 }
 
 func LoadJsonSchemaFile(path string) (*v0.Schematics, error) {
-	var s *Schematics
+	var s Schematics
 	s.Configs()
-	content, err := os.ReadFile(path)
-	if err != nil {
-		Logs.ERROR("Failed to load schema file", err)
+	baseSchematics := transformSchematics(s)
+	if err := LoadJsonSchemaFileInto(baseSchematics, path); err != nil {
 		return nil, err
 	}
-	var schema Schema
-	err = json.Unmarshal(content, &schema)
+	return baseSchematics, nil
+}
+
+// LoadJsonSchemaFileInto loads the schema file into the schematics, the validators, operators and conditions registered
+// on them are kept and the basic ones are added. With FailOnLintErrors set loading fails when Check finds errors
+func LoadJsonSchemaFileInto(schematics *v0.Schematics, path string) error {
+	schema, err := readSchemaFile(path)
 	if err != nil {
-		Logs.ERROR("Failed to unmarshall schema file", err)
-		return nil, err
+		Logs.ERROR("Failed to load schema file", err)
+		return err
 	}
-	s.Schema = schema
-
-	return transformSchematics(*s), nil
+	return load(schematics, schema, filepath.Dir(path), []string{path})
 }
 
 func LoadMap(schemaMap interface{}) (*v0.Schematics, error) {
-	var s *Schematics
+	var s Schematics
 	s.Configs()
+	baseSchematics := transformSchematics(s)
+	if err := LoadMapInto(baseSchematics, schemaMap); err != nil {
+		return nil, err
+	}
+	return baseSchematics, nil
+}
+
+// LoadMapInto loads the schema map into the schematics, see LoadJsonSchemaFileInto
+func LoadMapInto(schematics *v0.Schematics, schemaMap interface{}) error {
 	jsonBytes, err := json.Marshal(schemaMap)
 	if err != nil {
 		Logs.ERROR("Schema should be valid json map[string]interface", err)
-		return nil, err
+		return err
+	}
+	refs, err := utils.References(jsonBytes)
+	if err != nil {
+		Logs.ERROR("Schema should be valid json map[string]interface", err)
+		return err
+	}
+	jsonBytes, err = utils.ResolveReferences(jsonBytes, "")
+	if err != nil {
+		Logs.ERROR("Failed to resolve the references of the schema", err)
+		return err
+	}
+	if err := metaschema.Validate("v1", jsonBytes); err != nil {
+		Logs.ERROR("Invalid schema", err)
+		return err
 	}
 	var schema Schema
 	err = json.Unmarshal(jsonBytes, &schema)
 	if err != nil {
 		Logs.ERROR("Failed to unmarshall schema file", err)
-		return nil, err
+		return err
+	}
+	schema.resolved = v0.ResolvedReferences(refs)
+	return load(schematics, schema, "", nil)
+}
+
+// load sets the schema on the schematics and extends it, the files in the chain are already being extended
+func load(schematics *v0.Schematics, schema Schema, dir string, chain []string) error {
+	schematics.Validators.BasicValidators()
+	schematics.Operators.LoadBasicOperations()
+	schematics.Conditions.BasicConditions()
+	schematics.Schema = *transformSchema(schema)
+	if err := extendSchema(schematics, schema.Extends, dir, chain); err != nil {
+		Logs.ERROR("Failed to extend the schema", err)
+		return err
+	}
+	if err := schematics.Schema.CheckDependencies(); err != nil {
+		Logs.ERROR("Invalid dependencies in the schema", err)
+		return err
+	}
+	if err := schematics.Schema.CheckExpressions(); err != nil {
+		Logs.ERROR("Invalid expressions in the schema", err)
+		return err
 	}
-	s.Schema = schema
-	return transformSchematics(*s), nil
+	if schematics.FailOnLintErrors {
+		if err := schematics.Check(); err != nil {
+			Logs.ERROR("Invalid schema", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchemaFile reads the schema file and resolves its references
+func readSchemaFile(path string) (Schema, error) {
+	var schema Schema
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return schema, err
+	}
+	refs, err := utils.References(content)
+	if err != nil {
+		return schema, err
+	}
+	content, err = utils.ResolveReferences(content, filepath.Dir(path))
+	if err != nil {
+		return schema, err
+	}
+	if err := metaschema.Validate("v1", content); err != nil {
+		return schema, err
+	}
+	err = json.Unmarshal(content, &schema)
+	schema.resolved = v0.ResolvedReferences(refs)
+	return schema, err
+}
+
+// extendSchema merges the schema of the schematics on the base schema file it extends, the path of the base schema is
+// relative to dir and chain has the files that are already extended
+func extendSchema(schematics *v0.Schematics, extends string, dir string, chain []string) error {
+	if extends == "" {
+		return nil
+	}
+	path := extends
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	for _, extended := range chain {
+		if filepath.Clean(extended) == filepath.Clean(path) {
+			return fmt.Errorf("circular extends: %s", strings.Join(append(chain, path), " -> "))
+		}
+	}
+	schema, err := readSchemaFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to load the base schema %s: %w", extends, err)
+	}
+	base := v0.Schematics{Schema: *transformSchema(schema), Logging: schematics.Logging}
+	if err := extendSchema(&base, schema.Extends, filepath.Dir(path), append(chain, path)); err != nil {
+		return err
+	}
+	merged, conflicts := v0.MergeSchemas(base.Schema, schematics.Schema)
+	for _, conflict := range conflicts {
+		Logs.DEBUG("merge conflict", conflict.String())
+	}
+	merged.Resolved = append([]string{"extends " + extends}, merged.Resolved...)
+	schematics.Schema = merged
+	return nil
 }
 
 func transformSchematics(s Schematics) *v0.Schematics {
@@ -116,6 +265,7 @@ func transformSchematics(s Schematics) *v0.Schematics {
 	baseSchematics.Operators = s.Operators
 	baseSchematics.Validators.BasicValidators()
 	baseSchematics.Operators.LoadBasicOperations()
+	baseSchematics.Conditions.BasicConditions()
 	baseSchematics.Schema = *transformSchema(s.Schema)
 	if s.DB != nil {
 		baseSchematics.Schema.DB = utils.CombineTwoMaps(baseSchematics.Schema.DB, s.DB)
@@ -123,26 +273,67 @@ func transformSchematics(s Schematics) *v0.Schematics {
 	return &baseSchematics
 }
 
+// transformFields converts the fields into the v0 fields and their order, the nested fields are converted with their parent
+func transformFields(fields []Field) (map[v0.TargetKey]v0.Field, []v0.TargetKey) {
+	baseFields := make(map[v0.TargetKey]v0.Field)
+	order := make([]v0.TargetKey, 0, len(fields))
+	for _, field := range fields {
+		baseFields[v0.TargetKey(field.TargetKey)] = transformField(field)
+		order = append(order, v0.TargetKey(field.TargetKey))
+	}
+	return baseFields, order
+}
+
+func transformField(field Field) v0.Field {
+	baseField := v0.Field{
+		DependsOn:             field.DependsOn,
+		DisplayName:           field.DisplayName,
+		Name:                  field.Name,
+		Type:                  field.Type,
+		AddToDB:               field.AddToDB,
+		IsRequired:            field.IsRequired,
+		Description:           field.Description,
+		Validators:            transformComponents(field.Validators),
+		Operators:             transformComponents(field.Operators),
+		L10n:                  field.L10n,
+		AdditionalInformation: field.AdditionalInformation,
+		CollectAllErrors:      field.CollectAllErrors,
+		Nullable:              field.Nullable,
+		AllowEmpty:            field.AllowEmpty,
+		Default:               field.Default,
+		Coerce:                field.Coerce,
+		Merge:                 field.Merge,
+		RemoveValidators:      field.RemoveValidators,
+		RemoveOperators:       field.RemoveOperators,
+		When:                  field.When,
+		ValidatorsOrder:       field.validatorsOrder,
+		OperatorsOrder:        field.operatorsOrder,
+	}
+	if len(field.Fields) > 0 {
+		baseField.Fields, baseField.FieldsOrder = transformFields(field.Fields)
+	}
+	if field.Items != nil {
+		items := transformField(*field.Items)
+		baseField.Items = &items
+	}
+	return baseField
+}
+
 func transformSchema(schema Schema) *v0.Schema {
 	var baseSchema v0.Schema
 	baseSchema.Version = schema.Version
 	baseSchema.DB = schema.DB
-	baseSchema.Fields = make(map[v0.TargetKey]v0.Field)
-	for _, field := range schema.Fields {
-		baseSchema.Fields[v0.TargetKey(field.TargetKey)] = v0.Field{
-			DependsOn:             field.DependsOn,
-			Name:                  field.Name,
-			Type:                  field.Name,
-			AddToDB:               field.AddToDB,
-			IsRequired:            field.IsRequired,
-			Description:           field.Description,
-			Validators:            transformComponents(field.Validators),
-			Operators:             transformComponents(field.Operators),
-			L10n:                  field.L10n,
-			AdditionalInformation: field.AdditionalInformation,
+	baseSchema.CollectAllErrors = schema.CollectAllErrors
+	baseSchema.Strict = schema.Strict
+	baseSchema.AdditionalFields = schema.AdditionalFields
+	baseSchema.Fields, baseSchema.FieldsOrder = transformFields(schema.Fields)
+	baseSchema.Resolved = schema.resolved
+	if len(schema.Definitions) > 0 {
+		baseSchema.Definitions = make(map[string]v0.Field, len(schema.Definitions))
+		for name, definition := range schema.Definitions {
+			baseSchema.Definitions[name] = transformField(definition)
 		}
 	}
-
 	return &baseSchema
 }
 
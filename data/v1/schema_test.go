@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"errors"
+	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"strings"
+	"testing"
+)
+
+func TestLoadMapIntoConfiguredSchematics(t *testing.T) {
+	schema := map[string]interface{}{
+		"version": "1",
+		"fields":  []interface{}{map[string]interface{}{"target_key": "color", "validators": map[string]interface{}{"IsGreen": map[string]interface{}{}}}},
+	}
+	if err := LoadMapInto(&v0.Schematics{FailOnLintErrors: true}, schema); err == nil || !strings.Contains(err.Error(), "validator IsGreen is not registered") {
+		t.Fatalf("expected loading to fail, got %v", err)
+	}
+
+	configured := v0.Schematics{FailOnLintErrors: true}
+	configured.Validators.RegisterValidator("IsGreen", func(i interface{}, _ map[string]interface{}) error {
+		if i != "green" {
+			return errors.New("not green")
+		}
+		return nil
+	})
+	if err := LoadMapInto(&configured, schema); err != nil {
+		t.Fatalf("expected the custom validator to be known while loading, got %v", err)
+	}
+	if errs := configured.Validate(map[string]interface{}{"color": "red"}); !errs.HasErrors() {
+		t.Fatal("expected the custom validator to run")
+	}
+}
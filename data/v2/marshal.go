@@ -0,0 +1,160 @@
+package v2
+
+import (
+	"errors"
+	"fmt"
+	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"os"
+	"sort"
+)
+
+// Marshal returns the schema of the schematics in the v2 file format as indented JSON. The fields, validators and
+// operators keep their order and the conditions are written sorted by name, so loading the result gives the same
+// schematics. The tags of the fields do not exist in v2, a schema with tags is not marshaled. A schema loaded with
+// extends or $ref is not marshaled either, see v0.Schema.CheckStandalone
+func Marshal(schematics *v0.Schematics) ([]byte, error) {
+	if err := schematics.Schema.CheckStandalone(); err != nil {
+		schematics.Logging.ERROR("The schema can not be written", err)
+		return nil, err
+	}
+	schema, err := fromSchema(schematics.Schema)
+	if err != nil {
+		schematics.Logging.ERROR("The schema can not be written in the v2 format", err)
+		return nil, err
+	}
+	content, err := utils.MarshalIndent(utils.CompactJson(schema))
+	if err != nil {
+		schematics.Logging.ERROR("Failed to marshal the schema", err)
+		return nil, err
+	}
+	return content, nil
+}
+
+// Save writes the schema of the schematics to the file in the v2 file format, see Marshal
+func Save(schematics *v0.Schematics, path string) error {
+	content, err := Marshal(schematics)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		schematics.Logging.ERROR("Failed to save the schema file", err)
+		return err
+	}
+	return nil
+}
+
+// fromSchema converts the v0 schema back into the v2 schema, it is the reverse of transformSchema
+func fromSchema(baseSchema v0.Schema) (Schema, error) {
+	schema := Schema{
+		Version:          baseSchema.Version,
+		DB:               baseSchema.DB,
+		CollectAllErrors: baseSchema.CollectAllErrors,
+		Strict:           baseSchema.Strict,
+		AdditionalFields: baseSchema.AdditionalFields,
+		Extends:          baseSchema.Extends,
+	}
+	var err error
+	schema.Fields, err = fromFields(baseSchema.Fields, baseSchema.FieldsOrder)
+	if err != nil {
+		return schema, err
+	}
+	if len(baseSchema.Definitions) > 0 {
+		schema.Definitions = make(map[string]Field, len(baseSchema.Definitions))
+		for name, definition := range baseSchema.Definitions {
+			if schema.Definitions[name], err = fromField("", definition); err != nil {
+				return schema, fmt.Errorf("definitions.%s: %w", name, err)
+			}
+		}
+	}
+	return schema, nil
+}
+
+func fromFields(baseFields map[v0.TargetKey]v0.Field, order []v0.TargetKey) ([]Field, error) {
+	if baseFields == nil {
+		return nil, nil
+	}
+	fields := make([]Field, 0, len(baseFields))
+	for _, target := range v0.OrderedTargets(baseFields, order) {
+		field, err := fromField(string(target), baseFields[target])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", target, err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func fromField(target string, baseField v0.Field) (Field, error) {
+	if len(baseField.Tags) > 0 {
+		return Field{}, errors.New("tags are not supported by the v2 format")
+	}
+	field := Field{
+		DependsOn:             baseField.DependsOn,
+		DisplayName:           baseField.DisplayName,
+		Name:                  baseField.Name,
+		TargetKey:             target,
+		AddToDB:               baseField.AddToDB,
+		Type:                  baseField.Type,
+		IsRequired:            baseField.IsRequired,
+		Description:           baseField.Description,
+		Validators:            fromComponents(baseField.ValidatorComponents()),
+		Operators:             fromComponents(baseField.OperatorComponents()),
+		Conditions:            fromConditions(baseField.Conditions),
+		L10n:                  baseField.L10n,
+		AdditionalInformation: baseField.AdditionalInformation,
+		CollectAllErrors:      baseField.CollectAllErrors,
+		Nullable:              baseField.Nullable,
+		AllowEmpty:            baseField.AllowEmpty,
+		Default:               baseField.Default,
+		Coerce:                baseField.Coerce,
+		Merge:                 baseField.Merge,
+		RemoveValidators:      baseField.RemoveValidators,
+		RemoveOperators:       baseField.RemoveOperators,
+		When:                  baseField.When,
+	}
+	var err error
+	if len(baseField.Fields) > 0 {
+		if field.Fields, err = fromFields(baseField.Fields, baseField.FieldsOrder); err != nil {
+			return field, err
+		}
+	}
+	if baseField.Items != nil {
+		items, err := fromField("", *baseField.Items)
+		if err != nil {
+			return field, fmt.Errorf("items: %w", err)
+		}
+		field.Items = &items
+	}
+	return field, nil
+}
+
+func fromComponents(baseComponents []v0.Component) []Component {
+	var components []Component
+	for _, c := range baseComponents {
+		components = append(components, Component{
+			Name:       c.Name,
+			Attributes: c.Attributes,
+			Error:      c.Error,
+			L10n:       ComponentLocale{Name: c.L10n.Name, Error: c.L10n.Error},
+		})
+	}
+	return components
+}
+
+func fromConditions(baseConditions map[string]v0.Condition) []Condition {
+	names := make([]string, 0, len(baseConditions))
+	for name := range baseConditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var conditions []Condition
+	for _, name := range names {
+		condition := Condition{Name: name, Attributes: baseConditions[name].Attributes}
+		if action := baseConditions[name].Action; action != nil {
+			condition.Action = &ConditionalAction{Success: action.Success, Error: action.Error}
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
@@ -0,0 +1,146 @@
+package v2
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files, _ := filepath.Glob("../../test-data/schema/direct/v2/*.json")
+	for _, file := range files {
+		loaded, err := LoadJsonSchemaFile(file)
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		saved := filepath.Join(dir, filepath.Base(file))
+		if len(loaded.Schema.Resolved) > 0 {
+			if err := Save(loaded, saved); err == nil {
+				t.Fatalf("%s: expected the schema loaded with %v not to be saved", file, loaded.Schema.Resolved)
+			}
+			loaded.Schema.Resolved = nil
+		}
+		if err := Save(loaded, saved); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+		reloaded, err := LoadJsonSchemaFile(saved)
+		if err != nil {
+			t.Fatalf("%s: %v", saved, err)
+		}
+		if !reflect.DeepEqual(loaded.Schema, reloaded.Schema) {
+			t.Fatalf("%s changed after saving it:\n%+v\n%+v", file, loaded.Schema, reloaded.Schema)
+		}
+		resaved := saved + ".again"
+		if err := Save(reloaded, resaved); err != nil {
+			t.Fatal(err)
+		}
+		first, _ := os.ReadFile(saved)
+		second, _ := os.ReadFile(resaved)
+		if string(first) != string(second) {
+			t.Fatalf("%s is not saved the same way twice:\n%s\n%s", file, first, second)
+		}
+	}
+}
+
+func TestMarshalKeepsTheDeclaredOrder(t *testing.T) {
+	schematics, err := LoadMap(map[string]interface{}{
+		"version": "2",
+		"fields": []interface{}{
+			map[string]interface{}{"target_key": "name", "type": "string", "validators": []interface{}{
+				map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "^a"}},
+				map[string]interface{}{"name": "IsString"},
+				map[string]interface{}{"name": "MatchRegex", "attributes": map[string]interface{}{"regex": "z$"}},
+			}},
+			map[string]interface{}{"target_key": "age", "type": "number", "when": "name != null && name != \"\"",
+				"conditions": []interface{}{map[string]interface{}{"name": "FieldExists", "attributes": map[string]interface{}{"field": "name"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := Marshal(schematics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "version": "2",
+  "fields": [
+    {
+      "target_key": "name",
+      "type": "string",
+      "validators": [
+        {
+          "name": "MatchRegex",
+          "attributes": {
+            "regex": "^a"
+          }
+        },
+        {
+          "name": "IsString"
+        },
+        {
+          "name": "MatchRegex",
+          "attributes": {
+            "regex": "z$"
+          }
+        }
+      ]
+    },
+    {
+      "target_key": "age",
+      "type": "number",
+      "conditions": [
+        {
+          "name": "FieldExists",
+          "attributes": {
+            "field": "name"
+          }
+        }
+      ],
+      "when": "name != null && name != \"\""
+    }
+  ]
+}
+`
+	if string(content) != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, content)
+	}
+}
+
+func TestSaveResolvedSchemas(t *testing.T) {
+	for file, expected := range map[string]string{
+		"../../test-data/schema/direct/v2/example-overlay.json":    "the schema was loaded with extends example-base.json",
+		"../../test-data/schema/direct/v2/example-references.json": "the schema was loaded with $ref #/definitions/money, $ref common-definitions.json#/definitions/address,",
+	} {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schematics, err := LoadJsonSchemaFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Marshal(schematics); err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Fatalf("%s: expected %q, got %v", file, expected, err)
+		}
+		if err := Save(schematics, file); err == nil {
+			t.Fatalf("%s: expected the source not to be overwritten", file)
+		}
+		if current, _ := os.ReadFile(file); string(current) != string(source) {
+			t.Fatalf("%s: the source changed", file)
+		}
+
+		// without Resolved the schema is written standalone, the extends and $ref of the source are inlined
+		schematics.Schema.Resolved = nil
+		content, err := Marshal(schematics)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), `"extends"`) || strings.Contains(string(content), `"$ref"`) {
+			t.Fatalf("%s: expected a standalone schema, got\n%s", file, content)
+		}
+	}
+}
@@ -3,11 +3,15 @@ package v2
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/metaschema"
 	"github.com/ashbeelghouri/jsonschematics/operators"
 	"github.com/ashbeelghouri/jsonschematics/utils"
 	"github.com/ashbeelghouri/jsonschematics/validators"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 type Schematics struct {
@@ -22,9 +26,16 @@ type Schematics struct {
 }
 
 type Schema struct {
-	Version string                 `json:"version"`
-	Fields  []Field                `json:"fields"`
-	DB      map[string]interface{} `json:"DB"`
+	Version          string                 `json:"version"`
+	Fields           []Field                `json:"fields"`
+	DB               map[string]interface{} `json:"DB"`
+	CollectAllErrors bool                   `json:"collect_all_errors"`
+	Strict           bool                   `json:"strict"`
+	AdditionalFields *bool                  `json:"additional_fields"`
+	Definitions      map[string]Field       `json:"definitions"`
+	Extends          string                 `json:"extends"`
+	// resolved are the v0.Schema Resolved entries of the $ref that were inlined when the schema was read
+	resolved []string
 }
 
 type Field struct {
@@ -41,11 +52,28 @@ type Field struct {
 	Conditions            []Condition            `json:"conditions"`
 	L10n                  map[string]interface{} `json:"l10n"`
 	AdditionalInformation map[string]interface{} `json:"additional_information"`
+	CollectAllErrors      *bool                  `json:"collect_all_errors"`
+	Nullable              bool                   `json:"nullable"`
+	AllowEmpty            bool                   `json:"allow_empty"`
+	Default               interface{}            `json:"default"`
+	Coerce                bool                   `json:"coerce"`
+	Fields                []Field                `json:"fields"`
+	Items                 *Field                 `json:"items"`
+	Merge                 string                 `json:"merge"`
+	RemoveValidators      []string               `json:"remove_validators"`
+	RemoveOperators       []string               `json:"remove_operators"`
+	When                  string                 `json:"when"`
 }
 
 type Condition struct {
 	Name       string                 `json:"name"`
 	Attributes map[string]interface{} `json:"attributes"`
+	Action     *ConditionalAction     `json:"action"`
+}
+
+type ConditionalAction struct {
+	Success []string `json:"success"`
+	Error   []string `json:"error"`
 }
 
 type ComponentLocale struct {
@@ -62,37 +90,137 @@ type Component struct {
 
 func LoadJsonSchemaFile(path string) (*v0.Schematics, error) {
 	var s Schematics
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+	baseSchematics := transformSchematics(s)
+	if baseSchematics == nil {
+		return nil, errors.New("could not load the base schema")
 	}
-	var schema Schema
-	err = json.Unmarshal(content, &schema)
-	if err != nil {
+	if err := LoadJsonSchemaFileInto(baseSchematics, path); err != nil {
 		return nil, err
 	}
-	s.Schema = schema
-	baseSchematics := transformSchematics(s)
-	if baseSchematics != nil {
-		return baseSchematics, nil
-	} else {
-		return nil, errors.New("could not load the base schema")
+	return baseSchematics, nil
+}
+
+// LoadJsonSchemaFileInto loads the schema file into the schematics, the validators, operators and conditions registered
+// on them are kept and the basic ones are added. With FailOnLintErrors set loading fails when Check finds errors
+func LoadJsonSchemaFileInto(schematics *v0.Schematics, path string) error {
+	schema, err := readSchemaFile(path)
+	if err != nil {
+		return err
 	}
+	return load(schematics, schema, filepath.Dir(path), []string{path})
 }
 
 func LoadMap(schemaMap interface{}) (*v0.Schematics, error) {
 	var s Schematics
+	baseSchematics := transformSchematics(s)
+	if err := LoadMapInto(baseSchematics, schemaMap); err != nil {
+		return nil, err
+	}
+	return baseSchematics, nil
+}
+
+// LoadMapInto loads the schema map into the schematics, see LoadJsonSchemaFileInto
+func LoadMapInto(schematics *v0.Schematics, schemaMap interface{}) error {
 	jsonBytes, err := json.Marshal(schemaMap)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	refs, err := utils.References(jsonBytes)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err = utils.ResolveReferences(jsonBytes, "")
+	if err != nil {
+		return err
+	}
+	if err := metaschema.Validate("v2", jsonBytes); err != nil {
+		return err
 	}
 	var schema Schema
 	err = json.Unmarshal(jsonBytes, &schema)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	s.Schema = schema
-	return transformSchematics(s), nil
+	schema.resolved = v0.ResolvedReferences(refs)
+	return load(schematics, schema, "", nil)
+}
+
+// load sets the schema on the schematics and extends it, the files in the chain are already being extended
+func load(schematics *v0.Schematics, schema Schema, dir string, chain []string) error {
+	schematics.Validators.BasicValidators()
+	schematics.Operators.LoadBasicOperations()
+	schematics.Conditions.BasicConditions()
+	schematics.Schema = *transformSchema(schema)
+	if err := extendSchema(schematics, schema.Extends, dir, chain); err != nil {
+		return err
+	}
+	if err := schematics.Schema.CheckDependencies(); err != nil {
+		return err
+	}
+	if err := schematics.Schema.CheckExpressions(); err != nil {
+		return err
+	}
+	if schematics.FailOnLintErrors {
+		if err := schematics.Check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchemaFile reads the schema file and resolves its references
+func readSchemaFile(path string) (Schema, error) {
+	var schema Schema
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return schema, err
+	}
+	refs, err := utils.References(content)
+	if err != nil {
+		return schema, err
+	}
+	content, err = utils.ResolveReferences(content, filepath.Dir(path))
+	if err != nil {
+		return schema, err
+	}
+	if err := metaschema.Validate("v2", content); err != nil {
+		return schema, err
+	}
+	err = json.Unmarshal(content, &schema)
+	schema.resolved = v0.ResolvedReferences(refs)
+	return schema, err
+}
+
+// extendSchema merges the schema of the schematics on the base schema file it extends, the path of the base schema is
+// relative to dir and chain has the files that are already extended
+func extendSchema(schematics *v0.Schematics, extends string, dir string, chain []string) error {
+	if extends == "" {
+		return nil
+	}
+	path := extends
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	for _, extended := range chain {
+		if filepath.Clean(extended) == filepath.Clean(path) {
+			return fmt.Errorf("circular extends: %s", strings.Join(append(chain, path), " -> "))
+		}
+	}
+	schema, err := readSchemaFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to load the base schema %s: %w", extends, err)
+	}
+	base := v0.Schematics{Schema: *transformSchema(schema), Logging: schematics.Logging}
+	if err := extendSchema(&base, schema.Extends, filepath.Dir(path), append(chain, path)); err != nil {
+		return err
+	}
+	merged, conflicts := v0.MergeSchemas(base.Schema, schematics.Schema)
+	for _, conflict := range conflicts {
+		schematics.Logging.DEBUG("merge conflict", conflict.String())
+	}
+	merged.Resolved = append([]string{"extends " + extends}, merged.Resolved...)
+	schematics.Schema = merged
+	return nil
 }
 
 func transformSchematics(s Schematics) *v0.Schematics {
@@ -108,6 +236,7 @@ func transformSchematics(s Schematics) *v0.Schematics {
 	baseSchematics.Separator = s.Separator
 	baseSchematics.Validators.BasicValidators()
 	baseSchematics.Operators.LoadBasicOperations()
+	baseSchematics.Conditions.BasicConditions()
 	baseSchematics.Schema = *transformSchema(s.Schema)
 	if s.DB != nil {
 		baseSchematics.Schema.DB = utils.CombineTwoMaps(baseSchematics.Schema.DB, s.DB)
@@ -115,25 +244,66 @@ func transformSchematics(s Schematics) *v0.Schematics {
 	return &baseSchematics
 }
 
+// transformFields converts the fields into the v0 fields and their order, the nested fields are converted with their parent
+func transformFields(fields []Field) (map[v0.TargetKey]v0.Field, []v0.TargetKey) {
+	baseFields := make(map[v0.TargetKey]v0.Field)
+	order := make([]v0.TargetKey, 0, len(fields))
+	for _, field := range fields {
+		baseFields[v0.TargetKey(field.TargetKey)] = transformField(field)
+		order = append(order, v0.TargetKey(field.TargetKey))
+	}
+	return baseFields, order
+}
+
+func transformField(field Field) v0.Field {
+	baseField := v0.Field{
+		DependsOn:             field.DependsOn,
+		DisplayName:           field.DisplayName,
+		Name:                  field.Name,
+		AddToDB:               field.AddToDB,
+		Type:                  field.Type,
+		IsRequired:            field.IsRequired,
+		Description:           field.Description,
+		Validators:            transformComponents(field.Validators),
+		Operators:             transformComponents(field.Operators),
+		Conditions:            transformConditions(field.Conditions),
+		L10n:                  field.L10n,
+		AdditionalInformation: field.AdditionalInformation,
+		CollectAllErrors:      field.CollectAllErrors,
+		Nullable:              field.Nullable,
+		AllowEmpty:            field.AllowEmpty,
+		Default:               field.Default,
+		Coerce:                field.Coerce,
+		Merge:                 field.Merge,
+		RemoveValidators:      field.RemoveValidators,
+		RemoveOperators:       field.RemoveOperators,
+		When:                  field.When,
+		ValidatorPipeline:     transformPipeline(field.Validators),
+		OperatorPipeline:      transformPipeline(field.Operators),
+	}
+	if len(field.Fields) > 0 {
+		baseField.Fields, baseField.FieldsOrder = transformFields(field.Fields)
+	}
+	if field.Items != nil {
+		items := transformField(*field.Items)
+		baseField.Items = &items
+	}
+	return baseField
+}
+
 func transformSchema(schema Schema) *v0.Schema {
 	var baseSchema v0.Schema
 	baseSchema.Version = schema.Version
 	baseSchema.DB = schema.DB
-	baseSchema.Fields = make(map[v0.TargetKey]v0.Field)
-
-	for _, field := range schema.Fields {
-		baseSchema.Fields[v0.TargetKey(field.TargetKey)] = v0.Field{
-			DependsOn:             field.DependsOn,
-			Name:                  field.Name,
-			AddToDB:               field.AddToDB,
-			Type:                  field.Name,
-			IsRequired:            field.IsRequired,
-			Description:           field.Description,
-			Validators:            transformComponents(field.Validators),
-			Operators:             transformComponents(field.Operators),
-			Conditions:            transformConditions(field.Conditions),
-			L10n:                  field.L10n,
-			AdditionalInformation: field.AdditionalInformation,
+	baseSchema.CollectAllErrors = schema.CollectAllErrors
+	baseSchema.Strict = schema.Strict
+	baseSchema.AdditionalFields = schema.AdditionalFields
+	baseSchema.Fields, baseSchema.FieldsOrder = transformFields(schema.Fields)
+	baseSchema.Resolved = schema.resolved
+	if len(schema.Definitions) > 0 {
+		baseSchema.Definitions = make(map[string]v0.Field, len(schema.Definitions))
+		for name, definition := range schema.Definitions {
+			baseSchema.Definitions[name] = transformField(definition)
 		}
 	}
 	return &baseSchema
@@ -147,6 +317,22 @@ func CreateConstantLocale(locale ComponentLocale) v0.ConstantL10n {
 	return c
 }
 
+// transformPipeline keeps the order of the components and the components that share a name
+func transformPipeline(comp []Component) []v0.Component {
+	var pipeline []v0.Component
+	for _, c := range comp {
+		pipeline = append(pipeline, v0.Component{
+			Name: c.Name,
+			Constant: v0.Constant{
+				Attributes: c.Attributes,
+				Error:      c.Error,
+				L10n:       CreateConstantLocale(c.L10n),
+			},
+		})
+	}
+	return pipeline
+}
+
 func transformComponents(comp []Component) map[string]v0.Constant {
 	con := make(map[string]v0.Constant)
 	for _, c := range comp {
@@ -162,9 +348,13 @@ func transformComponents(comp []Component) map[string]v0.Constant {
 func transformConditions(cond []Condition) map[string]v0.Condition {
 	con := make(map[string]v0.Condition)
 	for _, c := range cond {
-		con[c.Name] = v0.Condition{
+		condition := v0.Condition{
 			Attributes: c.Attributes,
 		}
+		if c.Action != nil {
+			condition.Action = &v0.ConditionalAction{Success: c.Action.Success, Error: c.Action.Error}
+		}
+		con[c.Name] = condition
 	}
 	return con
 }
@@ -0,0 +1,151 @@
+package v2
+
+import (
+	"errors"
+	v0 "github.com/ashbeelghouri/jsonschematics/data/v0"
+	"github.com/ashbeelghouri/jsonschematics/errorHandler"
+	"strings"
+	"testing"
+)
+
+func errorTargets(errs *errorHandler.Errors) string {
+	var targets []string
+	for _, target := range errs.Targets() {
+		targets = append(targets, string(target))
+	}
+	return strings.Join(targets, ",")
+}
+
+func TestSchemaReferences(t *testing.T) {
+	schematics, err := LoadJsonSchemaFile("../../test-data/schema/direct/v2/example-references.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := schematics.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if !schematics.Schema.Fields["price"].IsRequired || schematics.Schema.Fields["discount"].IsRequired {
+		t.Fatal("the keys next to the reference should override the definition")
+	}
+	errs := schematics.Validate(map[string]interface{}{
+		"price":    -1,
+		"billing":  map[string]interface{}{"city": "Dubai", "country": "AE"},
+		"shipping": map[string]interface{}{"country": "FR"},
+	})
+	if got := errorTargets(errs); got != "price,shipping.city,shipping.country" {
+		t.Fatalf("unexpected errors %v", errs.GetStrings("en", "%target: %message"))
+	}
+
+	_, err = LoadMap(map[string]interface{}{
+		"version": "2",
+		"definitions": map[string]interface{}{
+			"a": map[string]interface{}{"fields": []interface{}{map[string]interface{}{"target_key": "b", "$ref": "#/definitions/b"}}},
+			"b": map[string]interface{}{"items": map[string]interface{}{"$ref": "#/definitions/a"}},
+		},
+		"fields": []interface{}{map[string]interface{}{"target_key": "a", "$ref": "#/definitions/a"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "circular reference") {
+		t.Fatalf("expected a circular reference error, got %v", err)
+	}
+}
+
+func TestExtendAndMergeSchemas(t *testing.T) {
+	schematics, err := LoadJsonSchemaFile("../../test-data/schema/direct/v2/example-overlay.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := schematics.Schema.Fields
+	if _, exists := fields["user.nickname"]; exists {
+		t.Fatal("the removed field should not be in the merged schema")
+	}
+	var names []string
+	for _, component := range fields["user.email"].ValidatorPipeline {
+		names = append(names, component.Name)
+	}
+	if strings.Join(names, ",") != "IsEmail,MatchRegex" || !fields["user.email"].IsRequired {
+		t.Fatalf("the appended validators should follow the base validators, got %v", names)
+	}
+	errs := schematics.Validate(map[string]interface{}{"user": map[string]interface{}{"email": "a@b.co", "name": "a very long name", "age": 10}})
+	if got := errorTargets(errs); got != "user.age,user.email,user.name" {
+		t.Fatalf("unexpected errors %v", errs.GetStrings("en", "%target: %message"))
+	}
+
+	base, err := LoadJsonSchemaFile("../../test-data/schema/direct/v2/example-base.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlay, err := LoadMap(map[string]interface{}{
+		"version": "2",
+		"fields": []interface{}{
+			map[string]interface{}{"target_key": "user.age"},
+			map[string]interface{}{"target_key": "user.name", "merge": "append", "remove_validators": []interface{}{"IsString"}},
+			map[string]interface{}{"target_key": "user.phone", "merge": "remove"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var conflicts []string
+	for _, conflict := range base.Merge(overlay) {
+		conflicts = append(conflicts, conflict.Target)
+	}
+	if strings.Join(conflicts, ",") != "user.age,user.name,user.phone" {
+		t.Fatalf("unexpected conflicts %v", conflicts)
+	}
+}
+
+func TestLoadMapErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []interface{}
+		expected string
+	}{
+		{
+			name: "meta schema",
+			fields: []interface{}{
+				map[string]interface{}{"target_key": "name", "validators": []interface{}{map[string]interface{}{"name": "IsString"}}},
+				map[string]interface{}{"target_key": "email", "validators": []interface{}{map[string]interface{}{"attributes": map[string]interface{}{}}}},
+				map[string]interface{}{"required": true},
+			},
+			expected: "the schema file is not valid: fields[1].validators[0].name is required, fields[2].target_key is required",
+		},
+		{
+			name: "lint errors",
+			fields: []interface{}{
+				map[string]interface{}{"target_key": "color", "validators": []interface{}{map[string]interface{}{"name": "IsGreen"}}},
+			},
+			expected: "the schema has errors: fields[color].validators[IsGreen]: validator IsGreen is not registered",
+		},
+	}
+	for _, test := range tests {
+		err := LoadMapInto(&v0.Schematics{FailOnLintErrors: true}, map[string]interface{}{"version": "2", "fields": test.fields})
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestLoadMapIntoConfiguredSchematics(t *testing.T) {
+	configured := v0.Schematics{FailOnLintErrors: true}
+	configured.Validators.RegisterValidator("IsGreen", func(i interface{}, _ map[string]interface{}) error {
+		if i != "green" {
+			return errors.New("not green")
+		}
+		return nil
+	})
+	err := LoadMapInto(&configured, map[string]interface{}{
+		"version": "2",
+		"fields": []interface{}{
+			map[string]interface{}{
+				"target_key": "color",
+				"validators": []interface{}{map[string]interface{}{"name": "IsGreen"}, map[string]interface{}{"name": "IsString"}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected the custom validator to be known while loading, got %v", err)
+	}
+	if got := errorTargets(configured.Validate(map[string]interface{}{"color": "red"})); got != "color" {
+		t.Fatalf("expected the custom validator to run, got %v", got)
+	}
+}
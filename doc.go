@@ -0,0 +1,3 @@
+// Package jsonschematics validates and manipulates JSON data using schematics,
+// the schema loaders live in data/v0, data/v1 and data/v2 and the request validation in api
+package jsonschematics
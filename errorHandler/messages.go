@@ -4,7 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ashbeelghouri/jsonschematics/utils"
-	"log"
+	"sort"
 	"strings"
 )
 
@@ -22,11 +22,14 @@ type Error struct {
 	L10n       ErrorL10n
 	Value      interface{}
 	ID         interface{}
+	Line       int
 	Data       map[string]interface{}
 }
 
 type Errors struct {
 	Messages map[Target]Error
+	// order keeps the targets in the order the errors were added, so the reports are deterministic
+	order []Target
 }
 
 func (e *Error) AddL10n(v string, local string, localeValidator string) {
@@ -62,6 +65,9 @@ func (e *Error) updateData(target string) Target {
 	e.Data["value"] = e.Value
 	e.Data["value"] = e.Value
 	e.Data["id"] = e.ID
+	if e.Line > 0 {
+		e.Data["line"] = e.Line
+	}
 	return Target(t)
 }
 
@@ -70,9 +76,52 @@ func (em *Errors) AddError(target string, err Error) {
 		em.Messages = make(map[Target]Error)
 	}
 	t := err.updateData(target)
+	if _, exists := em.Messages[t]; !exists {
+		em.order = append(em.order, t)
+	}
 	em.Messages[t] = err
 }
 
+// Targets returns the targets of all the errors in the order they were added,
+// targets that were written directly into the Messages are at the end in sorted order
+func (em *Errors) Targets() []Target {
+	if em == nil {
+		return nil
+	}
+	var targets []Target
+	seen := make(map[Target]bool, len(em.Messages))
+	for _, target := range em.order {
+		if _, exists := em.Messages[target]; exists && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	var rest []Target
+	for target := range em.Messages {
+		if !seen[target] {
+			rest = append(rest, target)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i] < rest[j]
+	})
+	return append(targets, rest...)
+}
+
+// SetLine marks all the errors with the line of the input they were found on
+func (em *Errors) SetLine(line int) {
+	if em == nil {
+		return
+	}
+	for target, err := range em.Messages {
+		err.Line = line
+		if err.Data != nil {
+			err.Data["line"] = line
+		}
+		em.Messages[target] = err
+	}
+}
+
 func (em *Errors) HasErrors() bool {
 	if em != nil {
 		for _, err := range em.Messages {
@@ -93,8 +142,8 @@ func (em *Errors) GetStrings(locale Locale, format string) *[]string {
 		format = "validation error %message for %target with validation on %validator, provided: %value: {%data}"
 	}
 
-	for target, msg := range em.Messages {
-		log.Println(target)
+	for _, target := range em.Targets() {
+		msg := em.Messages[target]
 		message, ok := msg.Message[locale]
 		if !ok {
 			continue
@@ -121,8 +170,8 @@ func (em *Errors) GetErrors(locale Locale, format string) *[]error {
 		format = "validation error %message for %target with validation on %validator, provided: %value"
 	}
 
-	for target, msg := range em.Messages {
-		log.Println(target)
+	for _, target := range em.Targets() {
+		msg := em.Messages[target]
 		message, ok := msg.Message[locale]
 		if !ok {
 			continue
@@ -161,7 +210,10 @@ func (em *Errors) MergeErrors(em2 *Errors) {
 	if em.Messages == nil {
 		em.Messages = make(map[Target]Error)
 	}
-	for target, err := range em2.Messages {
-		em.Messages[target] = err
+	for _, target := range em2.Targets() {
+		if _, exists := em.Messages[target]; !exists {
+			em.order = append(em.order, target)
+		}
+		em.Messages[target] = em2.Messages[target]
 	}
 }
@@ -0,0 +1,292 @@
+// Package metaschema has the JSON Schema (draft-07) of every schematics file format, they are used to validate the
+// schema files when they are loaded and can be given to editors for autocompletion, e.g. with "$schema" in the file
+package metaschema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed schemas/*.json
+var files embed.FS
+
+// Formats are the formats that have a meta schema, the api formats are the schemas of the api package
+var Formats = []string{"v0", "v1", "v2", "api/v0", "api/v1", "api/v2"}
+
+var (
+	parsed   = map[string]map[string]interface{}{}
+	parsedMu sync.Mutex
+)
+
+// Get returns the meta schema of the format as JSON
+func Get(format string) ([]byte, error) {
+	content, err := files.ReadFile("schemas/" + strings.ReplaceAll(format, "/", "-") + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("there is no meta schema for the format %s, the formats are %s", format, strings.Join(Formats, ", "))
+	}
+	return content, nil
+}
+
+// Violation is a part of the schema file that does not match the meta schema, Path is where it is in the file,
+// e.g. fields[3].validators[1].name
+type Violation struct {
+	Path    string
+	Message string
+}
+
+func (v Violation) Error() string {
+	if v.Path == "" {
+		return "schema " + v.Message
+	}
+	return v.Path + " " + v.Message
+}
+
+// Violations is the error returned by Validate
+type Violations []Violation
+
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Error()
+	}
+	return "the schema file is not valid: " + strings.Join(messages, ", ")
+}
+
+// Validate validates the schema file against the meta schema of the format, the error is Violations when the file
+// does not match it
+func Validate(format string, document []byte) error {
+	meta, err := load(format)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	v := validator{root: meta}
+	if violations := v.validate(meta, value, ""); len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+func load(format string) (map[string]interface{}, error) {
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	if meta, ok := parsed[format]; ok {
+		return meta, nil
+	}
+	content, err := Get(format)
+	if err != nil {
+		return nil, err
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal(content, &meta); err != nil {
+		return nil, err
+	}
+	parsed[format] = meta
+	return meta, nil
+}
+
+// validator validates the keywords used by the meta schemas: type, properties, additionalProperties, required, items,
+// $ref to the definitions, allOf and anyOf. A required property that is null is missing
+type validator struct {
+	root map[string]interface{}
+}
+
+func (v validator) validate(schema map[string]interface{}, value interface{}, path string) Violations {
+	if ref, ok := schema["$ref"].(string); ok {
+		definition, err := v.definition(ref)
+		if err != nil {
+			return Violations{{Path: path, Message: err.Error()}}
+		}
+		return v.validate(definition, value, path)
+	}
+
+	// null leaves the value unset when the schema file is decoded, so it is accepted for any type
+	if value == nil {
+		return nil
+	}
+	var violations Violations
+	if types := schemaTypes(schema); len(types) > 0 && !matchesType(value, types) {
+		return Violations{{Path: path, Message: "should be " + typeNames(types)}}
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, s := range allOf {
+			if sub, ok := s.(map[string]interface{}); ok {
+				violations = append(violations, v.validate(sub, value, path)...)
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		violations = append(violations, v.anyOf(anyOf, value, path)...)
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		violations = append(violations, v.object(schema, typed, path)...)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				violations = append(violations, v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return violations
+}
+
+func (v validator) object(schema map[string]interface{}, value map[string]interface{}, path string) Violations {
+	var violations Violations
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if property, ok := lookupKey(properties, key).(map[string]interface{}); ok {
+			violations = append(violations, v.validate(property, value[key], propertyPath(path, key))...)
+			continue
+		}
+		if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			violations = append(violations, v.validate(additional, value[key], fmt.Sprintf("%s[%s]", path, key))...)
+		}
+	}
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, r := range required {
+			if key, ok := r.(string); ok {
+				if lookupKey(value, key) == nil {
+					violations = append(violations, Violation{Path: propertyPath(path, key), Message: "is required"})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// lookupKey returns the value of the key, or of a key that only differs in case like the loaders do when they decode the
+// file, e.g. "Name" for "name" in the files written before the keys were lower case
+func lookupKey(object map[string]interface{}, key string) interface{} {
+	if value, exists := object[key]; exists {
+		return value
+	}
+	for k, value := range object {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
+// anyOf returns nothing when one of the schemas matches, otherwise the violations of the first schema of the same type
+// as the value, so the violation is as precise as it can be
+func (v validator) anyOf(schemas []interface{}, value interface{}, path string) Violations {
+	var types []string
+	var closest Violations
+	for _, s := range schemas {
+		sub, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		violations := v.validate(sub, value, path)
+		if len(violations) == 0 {
+			return nil
+		}
+		if subTypes := schemaTypes(sub); len(subTypes) > 0 && !matchesType(value, subTypes) {
+			types = append(types, subTypes...)
+			continue
+		}
+		if closest == nil {
+			closest = violations
+		}
+	}
+	if closest != nil {
+		return closest
+	}
+	return Violations{{Path: path, Message: "should be " + typeNames(types)}}
+}
+
+func (v validator) definition(ref string) (map[string]interface{}, error) {
+	name, ok := strings.CutPrefix(ref, "#/definitions/")
+	if ok {
+		definitions, _ := v.root["definitions"].(map[string]interface{})
+		if definition, exists := definitions[name].(map[string]interface{}); exists {
+			return definition, nil
+		}
+	}
+	return nil, fmt.Errorf("the meta schema reference %s is not found", ref)
+}
+
+func propertyPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func schemaTypes(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func matchesType(value interface{}, types []string) bool {
+	for _, t := range types {
+		switch typed := value.(type) {
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if _, err := typed.Int64(); err == nil && t == "integer" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeNames(types []string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "object", "array", "integer":
+			names[i] = "an " + t
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
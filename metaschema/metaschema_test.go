@@ -0,0 +1,65 @@
+package metaschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMetaSchemas(t *testing.T) {
+	for _, format := range Formats {
+		content, err := Get(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !json.Valid(content) {
+			t.Fatalf("the meta schema of %s is not valid json", format)
+		}
+	}
+}
+
+func TestValidateSchemaFiles(t *testing.T) {
+	for _, version := range []string{"v0", "v1", "v2"} {
+		files, _ := filepath.Glob("../test-data/schema/direct/" + version + "/*.json")
+		if len(files) == 0 {
+			t.Fatalf("no %s schema files", version)
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err = utils.ResolveReferences(content, filepath.Dir(file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Validate(version, content); err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+		}
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		document string
+		expected string
+	}{
+		{"valid", "v2", `{"version": "2", "fields": [{"target_key": "name"}]}`, ""},
+		{"missing key", "v2", `{"version": "2", "fields": [{"required": true}]}`, "the schema file is not valid: fields[0].target_key is required"},
+		{"wrong type", "v0", `{"version": "0", "fields": {"name": {"required": "yes"}}}`, "the schema file is not valid: fields[name].required should be a boolean"},
+		// the loaders decode the keys without case, like the files written before the keys were lower case
+		{"key case", "api/v2", `{"version": "2", "endpoints": {"/users": {"body": [{"target_key": "name", "validators": [{"Name": "IsString", "ErrMsg": "not a string"}]}]}}}`, ""},
+	}
+	for _, test := range tests {
+		err := Validate(test.format, []byte(test.document))
+		if got := fmt.Sprint(err); (test.expected == "" && err != nil) || (test.expected != "" && got != test.expected) {
+			t.Errorf("%s: expected %q, got %v", test.name, test.expected, err)
+		}
+	}
+}
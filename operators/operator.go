@@ -22,6 +22,8 @@ func (op *Operators) LoadBasicOperations() {
 	op.RegisterOperation("Capitalize", Capitalize)
 	op.RegisterOperation("UpperCase", UpperCase)
 	op.RegisterOperation("LowerCase", LowerCase)
+	op.RegisterOperation("Trim", Trim)
+	op.RegisterOperation("Replace", Replace)
 
 	// number operations
 	op.RegisterOperation("Add", Add)
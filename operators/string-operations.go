@@ -20,3 +20,31 @@ func LowerCase(i interface{}, _ map[string]interface{}) *interface{} {
 	var opResult interface{} = strings.ToLower(str)
 	return &opResult
 }
+
+func Trim(i interface{}, attr map[string]interface{}) *interface{} {
+	str, ok := i.(string)
+	if !ok {
+		return nil
+	}
+	var opResult interface{}
+	if cutset, ok := attr["cutset"].(string); ok {
+		opResult = strings.Trim(str, cutset)
+	} else {
+		opResult = strings.TrimSpace(str)
+	}
+	return &opResult
+}
+
+func Replace(i interface{}, attr map[string]interface{}) *interface{} {
+	str, ok := i.(string)
+	if !ok {
+		return nil
+	}
+	old, ok := attr["old"].(string)
+	if !ok {
+		return nil
+	}
+	replaceWith, _ := attr["new"].(string)
+	var opResult interface{} = strings.ReplaceAll(str, old, replaceWith)
+	return &opResult
+}
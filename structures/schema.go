@@ -36,8 +36,8 @@ type Field struct {
 }
 
 type Condition struct {
-	Action     string                 `json:"action"`
-	attributes map[string]interface{} `json:"attributes"`
+	Action     *ConditionalAction     `json:"action"`
+	Attributes map[string]interface{} `json:"attributes"`
 }
 
 type ConditionalAction struct {
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// CompactJson converts the value to what encoding/json would write for it, without the struct fields that decode to the
+// same value when they are missing: false, 0, "", nil pointers, maps and slices, and structs without other fields.
+// Empty maps and slices are kept since they are not nil once decoded, the values of interfaces are kept as they are
+// and the structs become OrderedObject with the fields in their declared order
+func CompactJson(value interface{}) interface{} {
+	compacted, _ := compactJson(reflect.ValueOf(value))
+	return compacted
+}
+
+// compactJson returns the compacted value and if it can be left out of a struct
+func compactJson(v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, true
+	}
+	if v.Type().Implements(jsonMarshaler) {
+		if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface || v.Kind() == reflect.Map) && v.IsNil() {
+			return nil, true
+		}
+		return v.Interface(), false
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, true
+		}
+		return v.Interface(), false
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, true
+		}
+		compacted, _ := compactJson(v.Elem())
+		return compacted, false
+	case reflect.Struct:
+		obj := NewOrderedObject()
+		compactStruct(v, obj)
+		return obj, obj.Len() == 0
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, true
+		}
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			keys = append(keys, name)
+			values[name] = v.MapIndex(key)
+		}
+		sort.Strings(keys)
+		obj := NewOrderedObject()
+		for _, key := range keys {
+			compacted, _ := compactJson(values[key])
+			obj.Set(key, compacted)
+		}
+		return obj, false
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, true
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i], _ = compactJson(v.Index(i))
+		}
+		return items, false
+	}
+	return v.Interface(), v.IsZero()
+}
+
+// compactStruct sets the fields of the struct on the object like encoding/json names them, the fields of embedded
+// structs without a name are promoted
+func compactStruct(v reflect.Value, obj *OrderedObject) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			compactStruct(v.Field(i), obj)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if compacted, omit := compactJson(v.Field(i)); !omit {
+			obj.Set(name, compacted)
+		}
+	}
+}
+
+// MarshalIndent is json.MarshalIndent with two spaces that does not escape <, > and &, so the expressions and
+// patterns are written as they are
+func MarshalIndent(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalUnescaped(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
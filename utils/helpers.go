@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"log"
@@ -33,16 +34,28 @@ func (d *DataMap) FlattenTheMap(data map[string]interface{}, prefix string, sepa
 		if prefix != "" {
 			newKey = prefix + separator + key
 		}
+		// null values and empty maps or arrays are kept as they are, so they can still be matched with the targets
+		if value == nil {
+			d.Data[newKey] = nil
+			continue
+		}
 		switch reflect.TypeOf(value).Kind() {
 		case reflect.Map:
 			if nestedMap, ok := value.(map[string]interface{}); ok {
-				d.FlattenTheMap(nestedMap, newKey, separator)
+				if len(nestedMap) == 0 {
+					d.Data[newKey] = nestedMap
+				} else {
+					d.FlattenTheMap(nestedMap, newKey, separator)
+				}
 			}
 		case reflect.Slice:
 			s := reflect.ValueOf(value)
+			if s.Len() == 0 {
+				d.Data[newKey] = value
+			}
 			for i := 0; i < s.Len(); i++ {
 				arrayKey := newKey + separator + strconv.Itoa(i)
-				if nestedMap, ok := s.Index(i).Interface().(map[string]interface{}); ok {
+				if nestedMap, ok := s.Index(i).Interface().(map[string]interface{}); ok && len(nestedMap) > 0 {
 					d.FlattenTheMap(nestedMap, arrayKey, separator)
 				} else {
 					d.Data[arrayKey] = s.Index(i).Interface()
@@ -56,45 +69,38 @@ func (d *DataMap) FlattenTheMap(data map[string]interface{}, prefix string, sepa
 
 func DeflateMap(data map[string]interface{}, separator string) map[string]interface{} {
 	result := make(map[string]interface{})
-
 	for flatKey, value := range data {
-		keys := strings.Split(flatKey, separator)
-		subMap := result
-
-		for i := 0; i < len(keys)-1; i++ {
-			key := keys[i]
-
-			if nextKeyIsIndex := i < len(keys)-1 && IsNumeric(keys[i+1]); nextKeyIsIndex {
-				if _, exists := subMap[key]; !exists {
-					subMap[key] = []interface{}{}
-				}
-
-				if reflect.TypeOf(subMap[key]).Kind() != reflect.Slice {
-					subMap[key] = []interface{}{}
-				}
-
-				slice := subMap[key].([]interface{})
-				index, _ := strconv.Atoi(keys[i+1])
-				for len(slice) <= index {
-					slice = append(slice, map[string]interface{}{})
-				}
-				subMap[key] = slice
-
-				subMap = slice[index].(map[string]interface{})
-				i++
-			} else {
-				if _, exists := subMap[key]; !exists {
-					subMap[key] = map[string]interface{}{}
-				}
+		insertPath(result, strings.Split(flatKey, separator), value)
+	}
+	return result
+}
 
-				subMap = subMap[key].(map[string]interface{})
+// insertPath sets the value at the keys inside the container and returns the container, an index key creates an array
+// that grows to fit the index and any other key creates an object
+func insertPath(container interface{}, keys []string, value interface{}) interface{} {
+	if len(keys) == 0 {
+		return value
+	}
+	key := keys[0]
+	index, err := strconv.Atoi(key)
+	isIndex := err == nil && index >= 0
+	switch c := container.(type) {
+	case map[string]interface{}:
+		c[key] = insertPath(c[key], keys[1:], value)
+		return c
+	case []interface{}:
+		if isIndex {
+			for len(c) <= index {
+				c = append(c, nil)
 			}
+			c[index] = insertPath(c[index], keys[1:], value)
+			return c
 		}
-
-		subMap[keys[len(keys)-1]] = value
 	}
-
-	return result
+	if isIndex {
+		return insertPath([]interface{}{}, keys, value)
+	}
+	return insertPath(map[string]interface{}{}, keys, value)
 }
 
 func IsNumeric(s string) bool {
@@ -126,13 +132,10 @@ func IsValidRegex(pattern string) bool {
 }
 
 func ConvertKeyToRegex(key string) string {
-	if !IsValidRegex(key) {
-		return key
-	}
 	// Escape special regex characters in the key except for *
 	escapedKey := regexp.QuoteMeta(key)
-	// Replace * with \d+ to match array indices
-	regexPattern := strings.ReplaceAll(escapedKey, `\*`, `\w+$`)
+	// Replace * with \w+ to match array indices and keys
+	regexPattern := strings.ReplaceAll(escapedKey, `\*`, `\w+`)
 	// Add start and end of line anchors
 
 	regexPattern = "^" + regexPattern + "$"
@@ -140,9 +143,14 @@ func ConvertKeyToRegex(key string) string {
 }
 
 func FindMatchingKeys(data map[string]interface{}, keyPattern string, separator string) map[string]interface{} {
+	re := regexp.MustCompile(ConvertKeyToRegex(keyPattern))
+	return FindMatchingKeysByRegex(data, re, keyPattern, separator)
+}
+
+// FindMatchingKeysByRegex is FindMatchingKeys with the regex of the key pattern already compiled
+func FindMatchingKeysByRegex(data map[string]interface{}, re *regexp.Regexp, keyPattern string, separator string) map[string]interface{} {
 	matchingKeys := make(map[string]interface{})
 	nestedKeys := make(map[string]interface{})
-	re := regexp.MustCompile(ConvertKeyToRegex(keyPattern))
 	// Collect all matching keys
 	for key, value := range data {
 		if re.MatchString(key) {
@@ -156,16 +164,29 @@ func FindMatchingKeys(data map[string]interface{}, keyPattern string, separator
 		nest := make(map[string]interface{})
 
 		for key, value := range nestedKeys {
-			log.Println("key:", key, "keyPattern: ", keyPattern, "separator: ", separator)
 			trimmedKey := strings.TrimPrefix(key, keyPattern+separator)
 			nest[trimmedKey] = value
 		}
 
-		matchingKeys[keyPattern] = nest
+		matchingKeys[keyPattern] = InflateValue(nest, separator)
 	}
 	return matchingKeys
 }
 
+// InflateValue builds the object or array back from its flat keys, the keys are relative to the object or array
+func InflateValue(nested map[string]interface{}, separator string) interface{} {
+	inflated := DeflateMap(nested, separator)
+	items := make([]interface{}, len(inflated))
+	for key, value := range inflated {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(items) {
+			return inflated
+		}
+		items[index] = value
+	}
+	return items
+}
+
 func GetFirstFromMap(mapped map[string]interface{}) interface{} {
 	for _, m := range mapped {
 		return m
@@ -189,9 +210,12 @@ func IsValidJson(content []byte) (string, interface{}) {
 	return "invalid format", nil
 }
 
+var pathParameter = regexp.MustCompile(`:[^/]+|\{[^/}]+}`)
+
+// GetPathRegex returns the regex of the endpoint path, * matches anything and the parameters :id and {id} match one segment
 func GetPathRegex(path string) string {
 	path = strings.ReplaceAll(path, "*", ".*")
-	path = strings.ReplaceAll(path, ":", "[^/]+")
+	path = pathParameter.ReplaceAllString(path, "[^/]+")
 	return "^" + path + "$"
 }
 
@@ -255,6 +279,35 @@ func getJsonFileAsMapArray(content []byte) ([]map[string]interface{}, error) {
 	return data, nil
 }
 
+// JsonObjectKeys returns the keys of the json object in the order they are written, nil when content is not an object
+func JsonObjectKeys(content []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil
+	}
+	var keys []string
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil
+		}
+		keys = append(keys, key)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil
+		}
+	}
+	return keys
+}
+
 func CombineTwoMaps(map1 map[string]interface{}, map2 map[string]interface{}) map[string]interface{} {
 	if len(map1) < 1 {
 		map1 = make(map[string]interface{})
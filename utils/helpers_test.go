@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFlattenTheMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "nested objects",
+			data:     map[string]interface{}{"user": map[string]interface{}{"name": map[string]interface{}{"first": "john"}}},
+			expected: map[string]interface{}{"user.name.first": "john"},
+		},
+		{
+			name:     "null and empty values",
+			data:     map[string]interface{}{"a": nil, "b": map[string]interface{}{}, "c": []interface{}{}},
+			expected: map[string]interface{}{"a": nil, "b": map[string]interface{}{}, "c": []interface{}{}},
+		},
+		{
+			name: "objects in arrays",
+			data: map[string]interface{}{"orders": []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{}, "x"}},
+			expected: map[string]interface{}{
+				"orders.0.id": 1,
+				"orders.1":    map[string]interface{}{},
+				"orders.2":    "x",
+			},
+		},
+	}
+	for _, test := range tests {
+		var dataMap DataMap
+		dataMap.FlattenTheMap(test.data, "", ".")
+		if fmt.Sprint(dataMap.Data) != fmt.Sprint(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, dataMap.Data)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OrderedObject is a json object that keeps the order of its keys, so the declared order of the validators is not lost
+type OrderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedObject() *OrderedObject {
+	return &OrderedObject{values: make(map[string]interface{})}
+}
+
+// Set sets the value of the key, a new key is added after the others
+func (o *OrderedObject) Set(key string, value interface{}) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete removes the key
+func (o *OrderedObject) Delete(key string) {
+	if _, exists := o.values[key]; !exists {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len is the number of keys
+func (o *OrderedObject) Len() int {
+	return len(o.keys)
+}
+
+func (o *OrderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyBytes, err := marshalUnescaped(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := marshalUnescaped(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyBytes)
+		buf.WriteByte(':')
+		buf.Write(valueBytes)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func decodeOrdered(content []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	return decodeOrderedValue(decoder)
+}
+
+func decodeOrderedValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+	switch delim {
+	case '{':
+		obj := NewOrderedObject()
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(keyToken.(string), value)
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case '[':
+		arr := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err = decoder.Token()
+		return arr, err
+	}
+	return nil, fmt.Errorf("unexpected %v in json", delim)
+}
+
+// ResolveReferences replaces every {"$ref": "..."} in the json content with the part of a document it points to, e.g.
+// "#/definitions/address" or "common.json#/definitions/address". The keys next to the $ref override the keys of the reference,
+// references to other files are relative to dir and circular references are returned as an error
+func ResolveReferences(content []byte, dir string) ([]byte, error) {
+	if !bytes.Contains(content, []byte(`"$ref"`)) {
+		return content, nil
+	}
+	document, err := decodeOrdered(content)
+	if err != nil {
+		return nil, err
+	}
+	r := referenceResolver{documents: make(map[string]interface{})}
+	resolved, err := r.resolve(document, document, dir, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+// ResolveReferencesKeepingCycles is ResolveReferences for recursive schemas, a circular reference is not an error, its
+// {"$ref": "..."} is kept in the content where it points back to a reference that is being resolved. Only the part of
+// the content at the json pointer is resolved and returned, e.g. /paths of an OpenAPI document, an empty pointer is the
+// whole content
+func ResolveReferencesKeepingCycles(content []byte, dir string, pointer string) ([]byte, error) {
+	document, err := decodeOrdered(content)
+	if err != nil {
+		return nil, err
+	}
+	node, err := lookupPointer(document, pointer)
+	if err != nil {
+		return nil, err
+	}
+	r := referenceResolver{documents: make(map[string]interface{}), keepCycles: true}
+	resolved, err := r.resolve(node, document, dir, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(resolved)
+}
+
+// References returns the $ref of the json content in the order they are found, every reference is returned once
+func References(content []byte) ([]string, error) {
+	if !bytes.Contains(content, []byte(`"$ref"`)) {
+		return nil, nil
+	}
+	document, err := decodeOrdered(content)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case *OrderedObject:
+			if ref, ok := n.values["$ref"].(string); ok && !StringInStrings(ref, refs) {
+				refs = append(refs, ref)
+			}
+			for _, key := range n.keys {
+				walk(n.values[key])
+			}
+		case []interface{}:
+			for _, item := range n {
+				walk(item)
+			}
+		}
+	}
+	walk(document)
+	return refs, nil
+}
+
+type referenceResolver struct {
+	// documents are the other files that are already loaded by their path
+	documents map[string]interface{}
+	// resolving are the references that are being resolved, a reference found again in them is circular
+	resolving []string
+	// keepCycles keeps the circular references instead of returning an error
+	keepCycles bool
+}
+
+func (r *referenceResolver) resolve(node interface{}, document interface{}, dir string, file string) (interface{}, error) {
+	switch n := node.(type) {
+	case *OrderedObject:
+		ref, isRef := n.values["$ref"].(string)
+		if !isRef {
+			return r.resolveKeys(n, document, dir, file)
+		}
+		return r.resolveReference(ref, n, document, dir, file)
+	case []interface{}:
+		arr := make([]interface{}, len(n))
+		for i, item := range n {
+			value, err := r.resolve(item, document, dir, file)
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = value
+		}
+		return arr, nil
+	}
+	return node, nil
+}
+
+// resolveKeys resolves the values of the object, a $ref of the object itself is kept as it is
+func (r *referenceResolver) resolveKeys(node *OrderedObject, document interface{}, dir string, file string) (*OrderedObject, error) {
+	obj := NewOrderedObject()
+	for _, key := range node.keys {
+		value, err := r.resolve(node.values[key], document, dir, file)
+		if err != nil {
+			return nil, err
+		}
+		obj.Set(key, value)
+	}
+	return obj, nil
+}
+
+func (r *referenceResolver) resolveReference(ref string, node *OrderedObject, document interface{}, dir string, file string) (interface{}, error) {
+	filePart, pointer, _ := strings.Cut(ref, "#")
+	targetDocument, targetDir, targetFile := document, dir, file
+	if filePart != "" {
+		targetFile = filePart
+		if !filepath.IsAbs(targetFile) {
+			targetFile = filepath.Join(dir, filePart)
+		}
+		var err error
+		if targetDocument, err = r.load(targetFile); err != nil {
+			return nil, fmt.Errorf("unable to load the reference %s: %w", ref, err)
+		}
+		targetDir = filepath.Dir(targetFile)
+	}
+
+	key := targetFile + "#" + pointer
+	for i, resolving := range r.resolving {
+		if resolving == key && r.keepCycles {
+			return r.resolveKeys(node, document, dir, file)
+		}
+		if resolving == key {
+			return nil, fmt.Errorf("circular reference: %s", strings.Join(append(r.resolving[i:], key), " -> "))
+		}
+	}
+	target, err := lookupPointer(targetDocument, pointer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve the reference %s: %w", ref, err)
+	}
+
+	r.resolving = append(r.resolving, key)
+	resolved, err := r.resolve(target, targetDocument, targetDir, targetFile)
+	r.resolving = r.resolving[:len(r.resolving)-1]
+	if err != nil {
+		return nil, err
+	}
+	if len(node.keys) == 1 {
+		return resolved, nil
+	}
+
+	resolvedObject, ok := resolved.(*OrderedObject)
+	if !ok {
+		return nil, fmt.Errorf("reference %s is not an object, it can not be merged with the keys next to it", ref)
+	}
+	merged := NewOrderedObject()
+	for _, k := range resolvedObject.keys {
+		merged.Set(k, resolvedObject.values[k])
+	}
+	for _, k := range node.keys {
+		if k == "$ref" {
+			continue
+		}
+		value, err := r.resolve(node.values[k], document, dir, file)
+		if err != nil {
+			return nil, err
+		}
+		merged.Set(k, value)
+	}
+	return merged, nil
+}
+
+func (r *referenceResolver) load(path string) (interface{}, error) {
+	if document, exists := r.documents[path]; exists {
+		return document, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	document, err := decodeOrdered(content)
+	if err != nil {
+		return nil, err
+	}
+	r.documents[path] = document
+	return document, nil
+}
+
+// lookupPointer finds the value of the json pointer, e.g. /definitions/address, an empty pointer is the whole document
+func lookupPointer(document interface{}, pointer string) (interface{}, error) {
+	node := document
+	if pointer == "" || pointer == "/" {
+		return node, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New("pointer should start with /")
+	}
+	for _, part := range strings.Split(pointer[1:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case *OrderedObject:
+			value, exists := n.values[part]
+			if !exists {
+				return nil, fmt.Errorf("%s not found", part)
+			}
+			node = value
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(n) {
+				return nil, fmt.Errorf("%s is not an index of the array", part)
+			}
+			node = n[index]
+		default:
+			return nil, fmt.Errorf("%s not found", part)
+		}
+	}
+	return node, nil
+}
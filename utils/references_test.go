@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		err      string
+	}{
+		{
+			name:     "without references",
+			content:  `{"b": 1, "a": 2}`,
+			expected: `{"b": 1, "a": 2}`,
+		},
+		{
+			name:     "keys next to the reference override it",
+			content:  `{"definitions": {"money": {"type": "number", "required": false}}, "price": {"$ref": "#/definitions/money", "required": true}}`,
+			expected: `{"definitions":{"money":{"type":"number","required":false}},"price":{"type":"number","required":true}}`,
+		},
+		{
+			name:     "other files",
+			content:  `{"address": {"$ref": "common-definitions.json#/definitions/address"}}`,
+			expected: `"address":{`,
+		},
+		{
+			name:    "circular references",
+			content: `{"definitions": {"a": {"b": {"$ref": "#/definitions/b"}}, "b": {"a": {"$ref": "#/definitions/a"}}}, "root": {"$ref": "#/definitions/a"}}`,
+			err:     "circular reference",
+		},
+	}
+	for _, test := range tests {
+		resolved, err := ResolveReferences([]byte(test.content), "../test-data/schema/direct/v2")
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected the error %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !strings.Contains(string(resolved), test.expected) || strings.Contains(string(resolved), `"$ref"`) {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, resolved)
+		}
+	}
+}
+
+func TestResolveReferencesKeepingCycles(t *testing.T) {
+	content := `{"definitions": {"node": {"children": {"items": {"$ref": "#/definitions/node"}}}}, "tree": {"$ref": "#/definitions/node"}}`
+	resolved, err := ResolveReferencesKeepingCycles([]byte(content), "", "/tree")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"children":{"items":{"$ref":"#/definitions/node"}}}`; string(resolved) != expected {
+		t.Fatalf("expected %s, got %s", expected, resolved)
+	}
+}
+
+func TestReferences(t *testing.T) {
+	refs, err := References([]byte(`{"a": {"$ref": "#/b"}, "c": [{"$ref": "other.json"}, {"$ref": "#/b"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(refs, ",") != "#/b,other.json" {
+		t.Fatalf("expected every reference once in order, got %v", refs)
+	}
+}
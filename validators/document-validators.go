@@ -0,0 +1,132 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+	"reflect"
+	"strings"
+)
+
+// Document is the flat data that is validated, Key is the flat key of the value the validator is called with
+type Document struct {
+	Data      map[string]interface{}
+	Key       string
+	Separator string
+}
+
+// Get returns the value of the target in the document. Every * in the target is replaced with the part of the Key at the same place,
+// so for the key items.2.end the target items.*.start is the start of the same item
+func (d Document) Get(target string) (interface{}, bool) {
+	separator := d.Separator
+	if separator == "" {
+		separator = "."
+	}
+	targetParts := strings.Split(target, separator)
+	keyParts := strings.Split(d.Key, separator)
+	for i, part := range targetParts {
+		if part == "*" && i < len(keyParts) {
+			targetParts[i] = keyParts[i]
+		}
+	}
+	target = strings.Join(targetParts, separator)
+
+	if value, exists := d.Data[target]; exists {
+		return value, true
+	}
+	matchingKeys := utils.FindMatchingKeys(d.Data, target, separator)
+	if value, exists := matchingKeys[target]; exists {
+		return value, true
+	}
+	if len(matchingKeys) == 1 {
+		return utils.GetFirstFromMap(matchingKeys), true
+	}
+	return nil, false
+}
+
+// otherField returns the name and the value of the target in the "field" attribute
+func otherField(attr map[string]interface{}, document Document) (string, interface{}, bool, error) {
+	target, ok := attr["field"].(string)
+	if !ok || target == "" {
+		return "", nil, false, errors.New("field attribute is required")
+	}
+	value, exists := document.Get(target)
+	return target, value, exists, nil
+}
+
+func equalValues(a interface{}, b interface{}) bool {
+	numberA, numberB := convertToFloat64(a), convertToFloat64(b)
+	if numberA != nil && numberB != nil {
+		return *numberA == *numberB
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// EqualsField checks that the value is equal to the value of the "field" attribute, e.g. the confirmation of a password
+func EqualsField(_ context.Context, i interface{}, attr map[string]interface{}, document Document) error {
+	target, value, exists, err := otherField(attr, document)
+	if err != nil {
+		return err
+	}
+	if !exists || !equalValues(i, value) {
+		return fmt.Errorf("value should be equal to %s", target)
+	}
+	return nil
+}
+
+// NotEqualsField checks that the value is not equal to the value of the "field" attribute
+func NotEqualsField(_ context.Context, i interface{}, attr map[string]interface{}, document Document) error {
+	target, value, exists, err := otherField(attr, document)
+	if err != nil {
+		return err
+	}
+	if exists && equalValues(i, value) {
+		return fmt.Errorf("value should not be equal to %s", target)
+	}
+	return nil
+}
+
+// GreaterThanField checks that the number is greater than the number of the "field" attribute, or equal to it when "or_equal" is true.
+// It passes when the other field is not provided
+func GreaterThanField(_ context.Context, i interface{}, attr map[string]interface{}, document Document) error {
+	target, value, exists, err := otherField(attr, document)
+	if err != nil || !exists {
+		return err
+	}
+	number := convertToFloat64(i)
+	if number == nil {
+		return fmt.Errorf("%v is not a number", i)
+	}
+	other := convertToFloat64(value)
+	if other == nil {
+		return fmt.Errorf("%s is not a number", target)
+	}
+	orEqual, _ := attr["or_equal"].(bool)
+	if *number < *other || (*number == *other && !orEqual) {
+		return fmt.Errorf("%v should be greater than %s", *number, target)
+	}
+	return nil
+}
+
+// DateAfterField checks that the date is after the date of the "field" attribute, or the same when "or_equal" is true.
+// It passes when the other field is not provided
+func DateAfterField(_ context.Context, i interface{}, attr map[string]interface{}, document Document) error {
+	target, value, exists, err := otherField(attr, document)
+	if err != nil || !exists {
+		return err
+	}
+	date := InterfaceToDate(i)
+	if date == nil {
+		return errors.New("invalid date provided")
+	}
+	other := InterfaceToDate(value)
+	if other == nil {
+		return fmt.Errorf("%s is not a valid date", target)
+	}
+	orEqual, _ := attr["or_equal"].(bool)
+	if date.Before(*other) || (date.Equal(*other) && !orEqual) {
+		return fmt.Errorf("date should be after %s", target)
+	}
+	return nil
+}
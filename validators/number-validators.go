@@ -99,7 +99,7 @@ func MaxAllowed(i interface{}, attributes map[string]interface{}) error {
 		return errors.New("max attribute should be a number")
 	}
 	if *number > *_max {
-		return errors.New(fmt.Sprintf("%d is greater than %d", *number, *_max))
+		return errors.New(fmt.Sprintf("%v is greater than %v", *number, *_max))
 	}
 	return nil
 }
@@ -117,7 +117,7 @@ func MinAllowed(i interface{}, attributes map[string]interface{}) error {
 		return errors.New("min attribute should be a number")
 	}
 	if *number < *_max {
-		return errors.New(fmt.Sprintf("%d is lesser than %d", *number, *_max))
+		return errors.New(fmt.Sprintf("%v is lesser than %v", *number, *_max))
 	}
 	return nil
 }
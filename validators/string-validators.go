@@ -38,23 +38,7 @@ func LIKE(i interface{}, attr map[string]interface{}) error {
 	}
 	pattern, ok := attr["pattern"].(string)
 	if ok {
-		replacer := strings.NewReplacer(
-			".", "\\.",
-			"+", "\\+",
-			"?", "\\?",
-			"(", "\\(",
-			")", "\\)",
-			"[", "\\[",
-			"]", "\\]",
-			"{", "\\{",
-			"}", "\\}",
-			"^", "\\^",
-			"$", "\\$",
-		)
-		regexPattern := replacer.Replace(pattern)
-		regexPattern = strings.ReplaceAll(regexPattern, "%", ".*")
-		regexPattern = strings.ReplaceAll(regexPattern, "_", ".")
-		regexPattern = "^" + regexPattern + "$"
+		regexPattern := LikeToRegex(pattern)
 
 		matched, _ := regexp.MatchString(regexPattern, str)
 
@@ -67,6 +51,27 @@ func LIKE(i interface{}, attr map[string]interface{}) error {
 	return nil
 }
 
+// LikeToRegex converts the pattern of LIKE to a regex, % matches any text and _ matches one character
+func LikeToRegex(pattern string) string {
+	replacer := strings.NewReplacer(
+		".", "\\.",
+		"+", "\\+",
+		"?", "\\?",
+		"(", "\\(",
+		")", "\\)",
+		"[", "\\[",
+		"]", "\\]",
+		"{", "\\{",
+		"}", "\\}",
+		"^", "\\^",
+		"$", "\\$",
+	)
+	regexPattern := replacer.Replace(pattern)
+	regexPattern = strings.ReplaceAll(regexPattern, "%", ".*")
+	regexPattern = strings.ReplaceAll(regexPattern, "_", ".")
+	return "^" + regexPattern + "$"
+}
+
 func IsEmail(i interface{}, attr map[string]interface{}) error {
 	isString := IsString(i, attr)
 	if isString != nil {
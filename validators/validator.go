@@ -1,22 +1,82 @@
 package validators
 
-import "github.com/ashbeelghouri/jsonschematics/utils"
+import (
+	"context"
+	"github.com/ashbeelghouri/jsonschematics/utils"
+)
 
 type Validators struct {
-	ValidationFns map[string]Validator
-	Logger        utils.Logger
+	ValidationFns         map[string]Validator
+	ContextValidationFns  map[string]ValidatorContext
+	DocumentValidationFns map[string]DocumentValidator
+	Logger                utils.Logger
 }
 
 type Validator func(interface{}, map[string]interface{}) error
 
+// ValidatorContext is a validator that receives the context of the validation call,
+// so it can stop its work when the context is cancelled or its deadline is exceeded
+type ValidatorContext func(context.Context, interface{}, map[string]interface{}) error
+
+// DocumentValidator is a validator that also receives the document that is validated, so it can compare the value with other values
+type DocumentValidator func(context.Context, interface{}, map[string]interface{}, Document) error
+
 func (v *Validators) RegisterValidator(name string, fn Validator) {
 	v.Logger.DEBUG("registering validator:", name)
 	if v.ValidationFns == nil {
 		v.ValidationFns = make(map[string]Validator)
 	}
+	delete(v.ContextValidationFns, name)
+	delete(v.DocumentValidationFns, name)
 	v.ValidationFns[name] = fn
 }
 
+func (v *Validators) RegisterValidatorContext(name string, fn ValidatorContext) {
+	v.Logger.DEBUG("registering context validator:", name)
+	if v.ContextValidationFns == nil {
+		v.ContextValidationFns = make(map[string]ValidatorContext)
+	}
+	delete(v.ValidationFns, name)
+	delete(v.DocumentValidationFns, name)
+	v.ContextValidationFns[name] = fn
+}
+
+func (v *Validators) RegisterDocumentValidator(name string, fn DocumentValidator) {
+	v.Logger.DEBUG("registering document validator:", name)
+	if v.DocumentValidationFns == nil {
+		v.DocumentValidationFns = make(map[string]DocumentValidator)
+	}
+	delete(v.ValidationFns, name)
+	delete(v.ContextValidationFns, name)
+	v.DocumentValidationFns[name] = fn
+}
+
+// GetDocument returns the validator registered with the name, the other validators are wrapped so they can be called with the document
+func (v *Validators) GetDocument(name string) (DocumentValidator, bool) {
+	if fn, ok := v.DocumentValidationFns[name]; ok {
+		return fn, true
+	}
+	if fn, ok := v.Get(name); ok {
+		return func(ctx context.Context, i interface{}, attr map[string]interface{}, _ Document) error {
+			return fn(ctx, i, attr)
+		}, true
+	}
+	return nil, false
+}
+
+// Get returns the validator registered with the name, plain validators are wrapped so they can be called with a context
+func (v *Validators) Get(name string) (ValidatorContext, bool) {
+	if fn, ok := v.ContextValidationFns[name]; ok {
+		return fn, true
+	}
+	if fn, ok := v.ValidationFns[name]; ok {
+		return func(_ context.Context, i interface{}, attr map[string]interface{}) error {
+			return fn(i, attr)
+		}, true
+	}
+	return nil, false
+}
+
 func (v *Validators) BasicValidators() {
 	v.Logger.DEBUG("loading all the basic validators")
 	// String Validators
@@ -36,7 +96,7 @@ func (v *Validators) BasicValidators() {
 	v.RegisterValidator("HaveURLHostName", HaveURLHostName)
 	v.RegisterValidator("HaveQueryParameter", HaveQueryParameter)
 	v.RegisterValidator("IsHttps", IsHttps)
-	v.RegisterValidator("IsURL", IsValidUuid)
+	v.RegisterValidator("IsValidUuid", IsValidUuid)
 	v.RegisterValidator("LIKE", LIKE)
 	v.RegisterValidator("MatchRegex", MatchRegex)
 
@@ -64,5 +124,11 @@ func (v *Validators) BasicValidators() {
 	v.RegisterValidator("StringsExistsInOptions", StringsExistsInOptions)
 	v.RegisterValidator("StringInOptions", StringInOptions)
 
+	// Document Validators
+	v.RegisterDocumentValidator("EqualsField", EqualsField)
+	v.RegisterDocumentValidator("NotEqualsField", NotEqualsField)
+	v.RegisterDocumentValidator("GreaterThanField", GreaterThanField)
+	v.RegisterDocumentValidator("DateAfterField", DateAfterField)
+
 	v.Logger.DEBUG("basic validators loaded")
 }
@@ -0,0 +1,30 @@
+package validators
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBasicUrlAndUuidValidators(t *testing.T) {
+	var v Validators
+	v.BasicValidators()
+	tests := []struct {
+		validator string
+		value     string
+		valid     bool
+	}{
+		{"IsURL", "https://example.com/path", true},
+		{"IsURL", "0b7e2f5c-4d8a-4c1e-9f3a-2a6b8c9d0e1f", false},
+		{"IsValidUuid", "0b7e2f5c-4d8a-4c1e-9f3a-2a6b8c9d0e1f", true},
+		{"IsValidUuid", "https://example.com/path", false},
+	}
+	for _, test := range tests {
+		fn, ok := v.Get(test.validator)
+		if !ok {
+			t.Fatalf("%s is not registered", test.validator)
+		}
+		if err := fn(context.Background(), test.value, map[string]interface{}{}); (err == nil) != test.valid {
+			t.Errorf("%s(%s): expected valid to be %v, got %v", test.validator, test.value, test.valid, err)
+		}
+	}
+}